@@ -22,7 +22,7 @@ func main() {
 
 	// Create scanner (fetches and caches all library sections)
 	ctx := context.Background()
-	scanner, err := plex.NewScanner(ctx, client)
+	scanner, err := plex.NewScanner(ctx, client, nil)
 	if err != nil {
 		log.Fatalf("Failed to create scanner: %v", err)
 	}
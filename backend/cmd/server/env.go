@@ -14,6 +14,8 @@ type serverConfig struct {
 	Concurrency int
 	Origins     []string
 	LogLevel    slog.Level
+	LogFile     string
+	StateDir    string
 }
 
 var defaultExts = []string{
@@ -38,12 +40,19 @@ func loadEnv(envpath string) serverConfig {
 	exts := tryLoadEnvStringList("SUPPORTED_EXTENSIONS", defaultExts)
 	origins := tryLoadEnvStringList("ALLOWED_ORIGINS", []string{"*"})
 	logLevel := parseLogLevel(os.Getenv("LOG_LEVEL"), slog.LevelInfo)
+	logFile := os.Getenv("LOG_FILE") // optional rotating file sink; empty disables it
+	stateDir := os.Getenv("STATE_DIR")
+	if stateDir == "" {
+		stateDir = "./state" // persists the scanqueue across restarts
+	}
 
 	return serverConfig{
 		Concurrency: concurrency,
 		Extensions:  exts,
 		Origins:     origins,
 		LogLevel:    logLevel,
+		LogFile:     logFile,
+		StateDir:    stateDir,
 	}
 }
 
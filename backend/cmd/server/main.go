@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"os"
 	"plexwatcher/internal/api"
+	"plexwatcher/internal/logring"
 	"plexwatcher/internal/response"
+	"plexwatcher/pkg/logger"
 	"strconv"
 
 	"github.com/lmittmann/tint"
@@ -52,23 +54,41 @@ func corsMiddleware(next http.Handler, allowedOrigins []string) http.Handler {
 	})
 }
 
-// configureLogger sets up the logger with the specified level
-// This can be called multiple times to reconfigure logging
-func configureLogger(level slog.Level) {
-	handler := tint.NewHandler(os.Stdout, &tint.Options{
+// configureLogger sets up the logger with the specified level. logFile, if
+// non-empty, adds a rotating plain-text sink alongside the colorized
+// console output. This can be called multiple times to reconfigure logging.
+func configureLogger(level slog.Level, logFile string) {
+	console := tint.NewHandler(os.Stdout, &tint.Options{
 		AddSource:  false,
 		Level:      level,
 		TimeFormat: "2006/01/02 15:04:05", // magic date `2006/01/02 15:04:05`
 	})
 
-	logger := slog.New(handler)
-	slog.SetDefault(logger)
+	handler := slog.Handler(console)
+	if logFile != "" {
+		rf, err := logger.NewRotatingFile(logFile, 10<<20, 3)
+		if err != nil {
+			slog.Warn("failed to open log file, continuing with console only", "path", logFile, "error", err)
+		} else {
+			file := slog.NewTextHandler(rf, &slog.HandlerOptions{Level: level})
+			handler = logger.NewMultiHandler(console, file)
+		}
+	}
+
+	// mirror every record into the in-memory ring backing /logs and
+	// /logs/tail, in addition to whatever console/file handler was built above
+	handler = logring.NewHandler(handler, logring.Default())
+
+	l := slog.New(handler)
+	slog.SetDefault(l)
+	logger.SetBase(l)
+	logger.LoadTraceEnv()
 }
 
 func init() {
 	// Bootstrap with INFO level - sufficient to log env parsing
 	// Will be reconfigured in main() after reading .env
-	configureLogger(slog.LevelInfo)
+	configureLogger(slog.LevelInfo, "")
 }
 
 func main() {
@@ -78,7 +98,7 @@ func main() {
 	conf := loadEnv(".env")
 
 	// Reconfigure logger with level from .env
-	configureLogger(conf.LogLevel)
+	configureLogger(conf.LogLevel, conf.LogFile)
 
 	slog.Info(
 		"Server started",
@@ -88,15 +108,14 @@ func main() {
 		"origins", conf.Origins,
 	)
 
-	api := api.NewAPI(context.Background(), conf.Concurrency, conf.Extensions)
+	h, err := api.NewHandler(context.Background(), conf.Concurrency, conf.Extensions, conf.StateDir)
+	if err != nil {
+		slog.Error("failed to initialize API handler", "error", err)
+		os.Exit(1)
+	}
 
 	mux := http.NewServeMux() // <-- create a new server mux (control the traffic). Request multiplexer
-	mux.HandleFunc("/", api.Root)
-	mux.HandleFunc("GET /status", api.GetStatus)
-	mux.HandleFunc("GET /prob-plex", api.ProbPlex)
-	mux.HandleFunc("POST /start", api.Start)
-	mux.HandleFunc("POST /stop", api.Stop)
-	mux.HandleFunc("POST /scan", api.Scan)
+	h.RegisterRoutes(mux)
 
 	slog.Info("Server listening", "port", port)
 	http.ListenAndServe(":"+strconv.Itoa(port), corsMiddleware(mux, conf.Origins))
@@ -4,10 +4,12 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
-	"sync"
 
+	"plexwatcher/internal/eventbus"
+	"plexwatcher/internal/plex"
+	"plexwatcher/internal/scanqueue"
 	"plexwatcher/internal/services/audiobookshelf"
-	"plexwatcher/internal/services/plex"
+	"plexwatcher/internal/services/jellyfin"
 	"plexwatcher/internal/watcher_manager"
 )
 
@@ -16,26 +18,39 @@ type Handler struct {
 	Context context.Context
 
 	plex              *plex.Scanner
+	plexServerURL     string // set alongside plex on start(), surfaced by /status
 	abs               *audiobookshelf.LibraryManager
-	scanSemaphore     chan struct{}   // limit concurrent scans
-	activeScansMutex  sync.Mutex      // protect activeScans map
-	activeScans       map[string]bool // track paths currently being scanned
+	jf                *jellyfin.LibraryManager
+	scanSemaphore     chan struct{} // limit concurrent scans
+	scanQueue         *scanqueue.Queue
+	workers           []*scanqueue.Worker // one per initialized service, set by startScanWorkers
+	workerCancel      context.CancelFunc  // stops the scanqueue workers started by start()
 	allowedExtensions []string
+	bus               *eventbus.Broker // fans out activity to /events subscribers
 }
 
-// NewHandler creates a new API handler with the specified concurrency limit for scans.
-func NewHandler(ctx context.Context, concurrency int, allowedExtensions []string) *Handler {
+// NewHandler creates a new API handler with the specified concurrency limit
+// for scans. stateDir is where the scanqueue persists outstanding scan
+// entries across restarts.
+func NewHandler(ctx context.Context, concurrency int, allowedExtensions []string, stateDir string) (*Handler, error) {
 	if concurrency <= 0 {
 		concurrency = 1 // at least 1
 		slog.Warn("concurrency must be at least 1, defaulting to 1")
 	}
+
+	queue, err := scanqueue.Open(stateDir)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Handler{
 		Watcher:           watcher_manager.NewManager(),
 		Context:           ctx,
 		scanSemaphore:     make(chan struct{}, concurrency), // limit to specified concurrent scans
-		activeScans:       make(map[string]bool),            // initialize deduplication map
+		scanQueue:         queue,
 		allowedExtensions: allowedExtensions,
-	}
+		bus:               eventbus.NewBroker(),
+	}, nil
 }
 
 // RegisterRoutes sets up the HTTP routes for the API.
@@ -44,9 +59,18 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/status", h.status)
 	mux.HandleFunc("/start", h.start)
 	mux.HandleFunc("/stop", h.stop)
+	mux.HandleFunc("/watcher", h.reconfigure)
+	mux.HandleFunc("/watcher/progress", h.progress)
+	mux.HandleFunc("/reset-breaker", h.resetBreaker)
 	mux.HandleFunc("/scan", h.scan)
+	mux.HandleFunc("/queue", h.queue)
+	mux.HandleFunc("/queue/flush", h.queueFlush)
 	mux.HandleFunc("/prob-plex", h.probPlex)
 	mux.HandleFunc("/prob-abs", h.probAudiobookshelf)
+	mux.HandleFunc("/prob-jellyfin", h.probJellyfin)
+	mux.HandleFunc("/events", h.events)
+	mux.HandleFunc("/logs", h.logs)
+	mux.HandleFunc("/logs/tail", h.logsTail)
 }
 
 func (h *Handler) root(w http.ResponseWriter, r *http.Request) {
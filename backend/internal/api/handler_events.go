@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"plexwatcher/internal/response"
+	"plexwatcher/pkg/logger"
+	"strconv"
+	"time"
+)
+
+const sseHeartbeatInterval = 15 * time.Second
+
+// events upgrades to Server-Sent Events and streams fs_watcher/scan activity
+// as it happens. Clients that reconnect with Last-Event-ID replay anything
+// they missed from the broker's ring buffer.
+func (h *Handler) events(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.WriteError(w, "method not allowed, expected GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		response.WriteError(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	var lastEventId uint64
+	if raw := r.Header.Get("Last-Event-ID"); raw != "" {
+		if v, err := strconv.ParseUint(raw, 10, 64); err == nil {
+			lastEventId = v
+		}
+	}
+
+	notify, drain, unsubscribe := h.bus.Subscribe(lastEventId)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	log := logger.For(logger.SubsystemAPI)
+	log.Debug("sse client connected", "last_event_id", lastEventId)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			log.Debug("sse client disconnected")
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		case _, open := <-notify:
+			if !open {
+				return
+			}
+			for _, e := range drain() {
+				b, err := json.Marshal(e)
+				if err != nil {
+					log.Error("failed to marshal sse event", "error", err)
+					continue
+				}
+				fmt.Fprintf(w, "id: %d\ndata: %s\n\n", e.Id, b)
+			}
+			flusher.Flush()
+		}
+	}
+}
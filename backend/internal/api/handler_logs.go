@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"plexwatcher/internal/logring"
+	"plexwatcher/internal/response"
+	"plexwatcher/pkg/logger"
+	"time"
+)
+
+// parseLevelParam parses the "level" query param (debug|info|warn|error),
+// defaulting to debug (i.e. no filtering) when absent or unrecognized.
+func parseLevelParam(raw string) slog.Level {
+	switch raw {
+	case "debug", "":
+		return slog.LevelDebug
+	case "info":
+		return slog.LevelInfo
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// logs serves the retained window of watcher activity as newline-delimited
+// JSON. Supports ?since=<rfc3339> and ?level=debug|info|warn|error filters.
+func (h *Handler) logs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.WriteError(w, "method not allowed, expected GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var since time.Time
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			response.WriteError(w, "invalid 'since', expected RFC3339", http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+	minLevel := parseLevelParam(r.URL.Query().Get("level"))
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	log := logger.For(logger.SubsystemAPI)
+	enc := json.NewEncoder(w)
+	for _, rec := range logring.Default().Query(since, minLevel) {
+		if err := enc.Encode(rec); err != nil {
+			log.Error("failed to encode log record", "error", err)
+			return
+		}
+	}
+}
+
+// logsTail upgrades to Server-Sent Events and streams new log records as
+// they're produced, filtered by the same ?level param as /logs.
+func (h *Handler) logsTail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.WriteError(w, "method not allowed, expected GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		response.WriteError(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	minLevel := parseLevelParam(r.URL.Query().Get("level"))
+
+	records, unsubscribe := logring.Default().Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	log := logger.For(logger.SubsystemAPI)
+	log.Debug("log tail client connected")
+
+	for {
+		select {
+		case <-r.Context().Done():
+			log.Debug("log tail client disconnected")
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		case rec, open := <-records:
+			if !open {
+				return
+			}
+			var lvl slog.Level
+			if err := lvl.UnmarshalText([]byte(rec.Level)); err == nil && lvl < minLevel {
+				continue
+			}
+			b, err := json.Marshal(rec)
+			if err != nil {
+				log.Error("failed to marshal log record", "error", err)
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", b)
+			flusher.Flush()
+		}
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
-	"plexwatcher/internal/http/response"
+	"plexwatcher/internal/response"
 	"plexwatcher/internal/services/audiobookshelf"
 )
 
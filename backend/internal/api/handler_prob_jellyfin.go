@@ -0,0 +1,48 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"plexwatcher/internal/response"
+	"plexwatcher/internal/services/jellyfin"
+)
+
+func (h *Handler) probJellyfin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.WriteError(w, "method not allowed, expected GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	serverUrl := params.Get("server_url")
+	if serverUrl == "" {
+		response.WriteError(w, "missing 'server_url' query parameter", http.StatusBadRequest)
+		return
+	}
+
+	apiKey := params.Get("api_key")
+	if apiKey == "" {
+		response.WriteError(w, "missing 'api_key' query parameter", http.StatusBadRequest)
+		return
+	}
+
+	client, err := jellyfin.NewClient(serverUrl, apiKey)
+	if err != nil {
+		response.WriteError(w, err.Error(), http.StatusBadRequest)
+		slog.Error("failed to create jellyfin client", "error", err)
+		return
+	}
+
+	libs, err := client.ListLibraries(h.Context)
+	if err != nil {
+		response.WriteError(w, err.Error(), http.StatusInternalServerError)
+		slog.Error("failed to list jellyfin libraries", "error", err)
+		return
+	}
+
+	for _, lib := range libs {
+		slog.Info("jellyfin library", "name", lib.Name, "id", lib.Id, "locations", lib.Locations)
+	}
+
+	response.WriteSuccess(w, "success hitting jellyfin server & retreived libraries", libs, http.StatusOK)
+}
@@ -1,14 +1,15 @@
 package api
 
 import (
-	"log"
-	"log/slog"
 	"net/http"
-	"plexwatcher/internal/http/response"
-	"plexwatcher/internal/services/plex"
+	"plexwatcher/internal/plex"
+	"plexwatcher/internal/response"
+	"plexwatcher/pkg/logger"
 )
 
 func (h *Handler) probPlex(w http.ResponseWriter, r *http.Request) {
+	log := logger.For(logger.SubsystemPlex)
+
 	// list plex sections
 	if r.Method != http.MethodGet {
 		response.WriteError(w, "method not allowed, expected GET", http.StatusMethodNotAllowed)
@@ -20,14 +21,14 @@ func (h *Handler) probPlex(w http.ResponseWriter, r *http.Request) {
 	serverUrl := params.Get("server_url")
 	if serverUrl == "" {
 		response.WriteError(w, "missing 'server_url' query parameter", http.StatusBadRequest)
-		log.Println("missing 'server_url' query parameter")
+		log.Warn("missing 'server_url' query parameter")
 		return
 	}
 
 	token := params.Get("token")
 	if token == "" {
 		response.WriteError(w, "missing 'token' query parameter", http.StatusBadRequest)
-		log.Println("missing 'token' query parameter")
+		log.Warn("missing 'token' query parameter")
 		return
 	}
 
@@ -35,19 +36,20 @@ func (h *Handler) probPlex(w http.ResponseWriter, r *http.Request) {
 	plexClient, err := plex.NewPlexClient(serverUrl, token)
 	if err != nil {
 		response.WriteError(w, err.Error(), http.StatusBadRequest)
-		slog.Error("failed to create PlexClient", "error", err)
+		log.Error("failed to create PlexClient", "error", err)
 		return
 	}
-	scanner, err := plex.NewScanner(h.Context, plexClient)
+	scanner, err := plex.NewScanner(h.Context, plexClient, nil)
 	if err != nil {
 		response.WriteError(w, err.Error(), http.StatusBadRequest)
-		slog.Error("failed to create PlexScanner", "error", err)
+		log.Error("failed to create PlexScanner", "error", err)
 		return
 	}
 
 	sections := scanner.GetAllSections()
 
-	slog.Info("plex server library section detected", "server", serverUrl, "sections", len(sections))
+	log.Info("plex server library section detected", "server", serverUrl, "sections", len(sections))
+	logger.Trace(logger.SubsystemPlex, "plex sections detail", "sections", sections)
 
 	response.WriteSuccess(w, "success hitting plex server & retreived library sections", sections, http.StatusOK)
 }
@@ -0,0 +1,12 @@
+package api
+
+import (
+	"net/http"
+	"plexwatcher/internal/response"
+)
+
+// progress returns the watcher's current recursive watch setup progress, so
+// callers can tell whether every subdirectory has a fsnotify watch yet.
+func (h *Handler) progress(w http.ResponseWriter, r *http.Request) {
+	response.WriteSuccess(w, "success retrieving watch progress", h.Watcher.Progress(), http.StatusOK)
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"plexwatcher/internal/response"
+)
+
+// queue returns the current scan queue depth per service.
+func (h *Handler) queue(w http.ResponseWriter, r *http.Request) {
+	response.WriteSuccess(w, "success retrieving queue depth", h.scanQueue.DepthByService(), http.StatusOK)
+}
+
+// queueFlush forces every worker to drain its queue immediately, bypassing
+// the cooldown and backoff gates a normal poll waits on.
+func (h *Handler) queueFlush(w http.ResponseWriter, r *http.Request) {
+	for _, worker := range h.workers {
+		go worker.Flush(h.Context)
+	}
+	slog.Info("scan queue flush requested", "workers", len(h.workers))
+	response.WriteSuccess(w, "queue flush triggered", nil, http.StatusOK)
+}
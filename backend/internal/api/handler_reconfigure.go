@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"plexwatcher/internal/response"
+	"plexwatcher/internal/types"
+)
+
+// reconfigure updates the running watcher's directory set without a full
+// stop/start, so debounce/settle state for unchanged directories survives.
+func (h *Handler) reconfigure(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		response.WriteError(w, "method not allowed, expected PATCH", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req types.RequestStart
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteError(w, err.Error(), http.StatusBadRequest)
+		slog.Error("failed to decode reconfigure request", "error", err)
+		return
+	}
+
+	if err := h.Watcher.Reconfigure(req); err != nil {
+		response.WriteError(w, err.Error(), http.StatusBadRequest)
+		slog.Error("failed to reconfigure watcher", "error", err)
+		return
+	}
+
+	slog.Info("watcher reconfigured", "dirs", req.WatchedDirs)
+	response.WriteSuccess(w, "watcher reconfigured", nil, http.StatusOK)
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"plexwatcher/internal/response"
+)
+
+// resetBreaker manually clears the circuit breaker back to closed for every
+// service, letting events resume flowing after an operator has fixed
+// whatever watch root tripped it.
+func (h *Handler) resetBreaker(w http.ResponseWriter, r *http.Request) {
+	h.Watcher.ResetBreaker()
+	slog.Info("circuit breaker manually reset")
+	response.WriteSuccess(w, "circuit breaker reset", nil, http.StatusOK)
+}
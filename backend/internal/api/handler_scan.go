@@ -5,10 +5,12 @@ import (
 	"log/slog"
 	"net/http"
 	"path/filepath"
-	"plexwatcher/internal/http/response"
+	"plexwatcher/internal/plex"
+	"plexwatcher/internal/response"
 	"plexwatcher/internal/services/audiobookshelf"
-	"plexwatcher/internal/services/plex"
+	"plexwatcher/internal/services/jellyfin"
 	"plexwatcher/internal/types"
+	"plexwatcher/pkg/logger"
 	"strings"
 )
 
@@ -23,44 +25,62 @@ func (h *Handler) scan(w http.ResponseWriter, r *http.Request) {
 
 	// Get Plex config from service_configs
 	if plexConfig, ok := req.ServiceConfigs[types.ServicePlex]; ok {
-		logger := slog.With("service", types.ServicePlex)
+		log := logger.With(logger.SubsystemScan, "service", types.ServicePlex)
 		plexClient, err := plex.NewPlexClient(plexConfig.ServerUrl, plexConfig.Token)
 		if err != nil {
 			response.WriteError(w, err.Error(), http.StatusBadRequest)
-			logger.Error("failed to create Plex client", "error", err)
+			log.Error("failed to create Plex client", "error", err)
 			return
 		}
-		scanner, err := plex.NewScanner(h.Context, plexClient)
+		scanner, err := plex.NewScanner(h.Context, plexClient, plexConfig.PathMappings)
 		if err != nil {
 			response.WriteError(w, err.Error(), http.StatusBadRequest)
-			logger.Error("failed to create Plex scanner", "error", err)
+			log.Error("failed to create Plex scanner", "error", err)
 			return
 		}
-		handlePlexManualScan(h, scanner, &req, logger)
+		handlePlexManualScan(h, scanner, &req, log)
 		response.WriteSuccess(w, "scanned triggered", nil, http.StatusOK)
 	}
 
 	if absConfig, ok := req.ServiceConfigs[types.ServiceAudiobookshelf]; ok {
-		logger := slog.With("service", types.ServiceAudiobookshelf)
-		absConfig, err := audiobookshelf.NewClient(absConfig.ServerUrl, absConfig.Token)
+		log := logger.With(logger.SubsystemScan, "service", types.ServiceAudiobookshelf)
+		absClient, err := audiobookshelf.NewClient(absConfig.ServerUrl, absConfig.Token)
 		if err != nil {
 			response.WriteError(w, err.Error(), http.StatusBadRequest)
-			logger.Error("failed to create audiobookshelf client", "error", err)
+			log.Error("failed to create audiobookshelf client", "error", err)
 			return
 		}
-		manager, err := audiobookshelf.NewLibraryManager(h.Context, absConfig)
+		manager, err := audiobookshelf.NewLibraryManager(h.Context, absClient, absConfig.PathMappings)
 		if err != nil {
 			response.WriteError(w, err.Error(), http.StatusBadRequest)
-			logger.Error("failed to create audiobookshelf library manager", "error", err)
+			log.Error("failed to create audiobookshelf library manager", "error", err)
 			return
 		}
-		handleAbsManualScan(h, manager, &req, logger)
+		handleAbsManualScan(h, manager, &req, log)
+		response.WriteSuccess(w, "scanned triggered", nil, http.StatusOK)
+	}
+
+	if jfConfig, ok := req.ServiceConfigs[types.ServiceJellyfin]; ok {
+		log := logger.With(logger.SubsystemScan, "service", types.ServiceJellyfin)
+		jfClient, err := jellyfin.NewClient(jfConfig.ServerUrl, jfConfig.Token)
+		if err != nil {
+			response.WriteError(w, err.Error(), http.StatusBadRequest)
+			log.Error("failed to create jellyfin client", "error", err)
+			return
+		}
+		manager, err := jellyfin.NewLibraryManager(h.Context, jfClient)
+		if err != nil {
+			response.WriteError(w, err.Error(), http.StatusBadRequest)
+			log.Error("failed to create jellyfin library manager", "error", err)
+			return
+		}
+		handleJellyfinManualScan(h, manager, &req, log)
 		response.WriteSuccess(w, "scanned triggered", nil, http.StatusOK)
 	}
 }
 
-func handleAbsManualScan(h *Handler, libManager *audiobookshelf.LibraryManager, req *types.RequestScan, logger *slog.Logger) {
-	logger.Info("trigger manual scans", "path_count", len(req.Paths))
+func handleAbsManualScan(h *Handler, libManager *audiobookshelf.LibraryManager, req *types.RequestScan, log *slog.Logger) {
+	log.Info("trigger manual scans", "path_count", len(req.Paths))
 	for _, path := range req.Paths {
 		targetDir := filepath.ToSlash(filepath.Dir(path))
 
@@ -68,23 +88,40 @@ func handleAbsManualScan(h *Handler, libManager *audiobookshelf.LibraryManager,
 			h.scanSemaphore <- struct{}{}
 			defer func() { <-h.scanSemaphore }()
 			if err := manager.ScanPath(h.Context, path); err != nil {
-				logger.Error("scan failed", "path", path, "error", err)
+				log.Error("scan failed", "path", path, "error", err)
+			} else {
+				log.Info("scan completed", "path", path)
+			}
+		}(targetDir, libManager)
+	}
+}
+
+func handleJellyfinManualScan(h *Handler, libManager *jellyfin.LibraryManager, req *types.RequestScan, log *slog.Logger) {
+	log.Info("trigger manual scans", "path_count", len(req.Paths))
+	for _, path := range req.Paths {
+		targetDir := filepath.ToSlash(filepath.Dir(path))
+
+		go func(path string, manager *jellyfin.LibraryManager) {
+			h.scanSemaphore <- struct{}{}
+			defer func() { <-h.scanSemaphore }()
+			if err := manager.ScanPath(h.Context, path); err != nil {
+				log.Error("scan failed", "path", path, "error", err)
 			} else {
-				logger.Info("scan completed", "path", path)
+				log.Info("scan completed", "path", path)
 			}
 		}(targetDir, libManager)
 	}
 }
 
-func handlePlexManualScan(h *Handler, scanner *plex.Scanner, req *types.RequestScan, logger *slog.Logger) {
+func handlePlexManualScan(h *Handler, scanner *plex.Scanner, req *types.RequestScan, log *slog.Logger) {
 
-	logger.Info("triggering manual scans", "path_count", len(req.Paths))
+	log.Info("triggering manual scans", "path_count", len(req.Paths))
 
 	for _, path := range req.Paths {
 		// map to plex path first
-		plexPath, section := scanner.MapToPlexPath(path)
-		if section == nil {
-			logger.Warn("failed to map to any plex library path, skipping scan", "path", path)
+		plexPath, _, ok := scanner.MapToPlexPath(path)
+		if !ok {
+			log.Warn("failed to map to any plex library path, skipping scan", "path", path)
 			continue
 		}
 
@@ -100,7 +137,7 @@ func handlePlexManualScan(h *Handler, scanner *plex.Scanner, req *types.RequestS
 			targetDir = filepath.Dir(plexPath)
 		} else {
 			// case 3: invalid extension. skip.
-			logger.Warn("disallowed extension found, skipping scan", "path", path, "extension", ext)
+			log.Warn("disallowed extension found, skipping scan", "path", path, "extension", ext)
 			continue
 		}
 
@@ -110,10 +147,10 @@ func handlePlexManualScan(h *Handler, scanner *plex.Scanner, req *types.RequestS
 		go func(p string, s *plex.Scanner) {
 			h.scanSemaphore <- struct{}{}        // acquire a token
 			defer func() { <-h.scanSemaphore }() // release the token
-			if section, err := s.ScanPath(h.Context, p); err != nil {
-				logger.Error("scan failed", "path", p, "error", err)
+			if err := s.ScanPath(h.Context, p, 0); err != nil {
+				log.Error("scan failed", "path", p, "error", err)
 			} else {
-				logger.Info("scan completed", "path", p, "section", section.SectionTitle)
+				log.Info("scan completed", "path", p)
 			}
 		}(targetDir, scanner)
 	}
@@ -1,15 +1,23 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http"
 	"path/filepath"
+	"plexwatcher/internal/eventbus"
 	"plexwatcher/internal/fs_watcher"
-	"plexwatcher/internal/http/response"
+	"plexwatcher/internal/plex"
+	"plexwatcher/internal/response"
+	"plexwatcher/internal/scanqueue"
 	"plexwatcher/internal/services/audiobookshelf"
-	"plexwatcher/internal/services/plex"
+	"plexwatcher/internal/services/jellyfin"
 	"plexwatcher/internal/types"
+	"plexwatcher/pkg/logger"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
 )
 
 // start the watcher with provided configuration
@@ -30,12 +38,13 @@ func (h *Handler) start(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		// initialize scanner
-		h.plex, err = plex.NewScanner(h.Context, plexClient)
+		h.plex, err = plex.NewScanner(h.Context, plexClient, plexConfig.PathMappings)
 		if err != nil {
 			response.WriteError(w, err.Error(), http.StatusBadRequest)
 			slog.Error("failed to create Plex scanner", "error", err)
 			return
 		}
+		h.plexServerURL = plexConfig.ServerUrl
 
 		// log all root sections
 		for _, section := range h.plex.GetAllSections() {
@@ -57,7 +66,7 @@ func (h *Handler) start(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		// initialize lib manager
-		h.abs, err = audiobookshelf.NewLibraryManager(h.Context, absClient)
+		h.abs, err = audiobookshelf.NewLibraryManager(h.Context, absClient, absConfig.PathMappings)
 		if err != nil {
 			response.WriteError(w, err.Error(), http.StatusBadRequest)
 			slog.Error("failed to create Audiobookshelf library manager", "error", err)
@@ -76,9 +85,39 @@ func (h *Handler) start(w http.ResponseWriter, r *http.Request) {
 		slog.Info("audiobookshelf service initialized", "server", absConfig.ServerUrl)
 	}
 
+	// Initialize Jellyfin/Emby if configured
+	if jfConfig, ok := req.ServiceConfigs[types.ServiceJellyfin]; ok {
+		jfClient, err := jellyfin.NewClient(jfConfig.ServerUrl, jfConfig.Token)
+		if err != nil {
+			response.WriteError(w, err.Error(), http.StatusBadRequest)
+			slog.Error("failed to create Jellyfin client", "error", err)
+			return
+		}
+		// initialize lib manager
+		h.jf, err = jellyfin.NewLibraryManager(h.Context, jfClient)
+		if err != nil {
+			response.WriteError(w, err.Error(), http.StatusBadRequest)
+			slog.Error("failed to create Jellyfin library manager", "error", err)
+			return
+		}
+
+		// log all lib
+		for _, lib := range h.jf.ListLibraries() {
+			slog.Info("jellyfin library",
+				"title", lib.Name,
+				"id", lib.Id,
+				"locations", lib.Locations,
+			)
+		}
+
+		slog.Info("jellyfin service initialized", "server", jfConfig.ServerUrl)
+	}
+
 	// start watcher
 	h.Watcher.RegisterHandler(types.ServicePlex, h.handlePlexUpdate)
 	h.Watcher.RegisterHandler(types.ServiceAudiobookshelf, h.handleAbsUpdate)
+	h.Watcher.RegisterHandler(types.ServiceJellyfin, h.handleJellyfinUpdate)
+	h.Watcher.SetBreakerRecoverHook(h.rescanWatchDirs)
 	if err := h.Watcher.Start(req); err != nil {
 		response.WriteError(w, err.Error(), http.StatusBadRequest)
 		slog.Error("failed to start watcher", "error", err)
@@ -89,107 +128,222 @@ func (h *Handler) start(w http.ResponseWriter, r *http.Request) {
 		"cooldown", req.Cooldown,
 	)
 
+	h.startScanWorkers(time.Duration(req.Cooldown) * time.Second)
+
 	response.WriteSuccess(w, "watcher started", nil, http.StatusOK)
 }
 
+// startScanWorkers launches one scanqueue worker per initialized service.
+// Each worker drains entries that have been quiet for cooldown, dispatching
+// to the matching scanner and removing the entry on success. Outstanding
+// entries left over from a crash or restart are replayed immediately.
+// Calling this again (e.g. on a restart) cancels any previously running
+// workers first.
+func (h *Handler) startScanWorkers(cooldown time.Duration) {
+	if h.workerCancel != nil {
+		h.workerCancel()
+	}
+	ctx, cancel := context.WithCancel(h.Context)
+	h.workerCancel = cancel
+	h.workers = h.workers[:0]
+
+	if h.plex != nil {
+		worker := &scanqueue.Worker{
+			Queue:    h.scanQueue,
+			Service:  string(types.ServicePlex),
+			Cooldown: cooldown,
+			Scan:     h.scanPlexTarget,
+		}
+		h.workers = append(h.workers, worker)
+		go worker.Run(ctx)
+	}
+	if h.abs != nil {
+		worker := &scanqueue.Worker{
+			Queue:    h.scanQueue,
+			Service:  string(types.ServiceAudiobookshelf),
+			Cooldown: cooldown,
+			Scan:     h.scanAbsTarget,
+		}
+		h.workers = append(h.workers, worker)
+		go worker.Run(ctx)
+	}
+	if h.jf != nil {
+		worker := &scanqueue.Worker{
+			Queue:    h.scanQueue,
+			Service:  string(types.ServiceJellyfin),
+			Cooldown: cooldown,
+			Scan:     h.scanJellyfinTarget,
+		}
+		h.workers = append(h.workers, worker)
+		go worker.Run(ctx)
+	}
+}
+
+// scanPlexTarget runs one Plex scan drained from the queue, under the
+// concurrency semaphore, publishing dispatch/result events along the way.
+func (h *Handler) scanPlexTarget(ctx context.Context, target string) error {
+	h.scanSemaphore <- struct{}{}
+	defer func() { <-h.scanSemaphore }()
+
+	h.bus.Publish(eventbus.Event{Kind: eventbus.KindScanDispatch, Service: string(types.ServicePlex), Target: target})
+
+	start := time.Now()
+	result := eventbus.Event{Kind: eventbus.KindScanResult, Service: string(types.ServicePlex), Target: target}
+	err := h.plex.ScanPath(ctx, target, 0)
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		slog.Error("scan failed", "scan_target", target, "error", err)
+		result.Error = err.Error()
+		h.bus.Publish(result)
+		return err
+	}
+	slog.Info("scan triggered", "scan_target", target)
+	h.bus.Publish(result)
+	return nil
+}
+
+// scanAbsTarget runs one Audiobookshelf scan drained from the queue.
+func (h *Handler) scanAbsTarget(ctx context.Context, target string) error {
+	h.scanSemaphore <- struct{}{}
+	defer func() { <-h.scanSemaphore }()
+
+	h.bus.Publish(eventbus.Event{Kind: eventbus.KindScanDispatch, Service: string(types.ServiceAudiobookshelf), Target: target})
+
+	start := time.Now()
+	result := eventbus.Event{Kind: eventbus.KindScanResult, Service: string(types.ServiceAudiobookshelf), Target: target}
+	err := h.abs.ScanPath(ctx, target)
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		slog.Error("audiobookshelf scan failed", "path", target, "error", err)
+		result.Error = err.Error()
+		h.bus.Publish(result)
+		return err
+	}
+	slog.Info("audiobookshelf scan succeeded", "path", target)
+	h.bus.Publish(result)
+	return nil
+}
+
+// scanJellyfinTarget runs one Jellyfin/Emby scan drained from the queue.
+func (h *Handler) scanJellyfinTarget(ctx context.Context, target string) error {
+	h.scanSemaphore <- struct{}{}
+	defer func() { <-h.scanSemaphore }()
+
+	h.bus.Publish(eventbus.Event{Kind: eventbus.KindScanDispatch, Service: string(types.ServiceJellyfin), Target: target})
+
+	start := time.Now()
+	result := eventbus.Event{Kind: eventbus.KindScanResult, Service: string(types.ServiceJellyfin), Target: target}
+	err := h.jf.ScanPath(ctx, target)
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		slog.Error("jellyfin scan failed", "path", target, "error", err)
+		result.Error = err.Error()
+		h.bus.Publish(result)
+		return err
+	}
+	slog.Info("jellyfin scan succeeded", "path", target)
+	h.bus.Publish(result)
+	return nil
+}
+
+// rescanWatchDirs queues one full-directory rescan per top-level watch root
+// once service's circuit breaker closes again after a trip, coalescing
+// whatever fine-grained events it dropped while open into a single
+// deferred scan per root via the existing scanqueue dedup.
+func (h *Handler) rescanWatchDirs(service types.ServiceType, watchDirs []types.WatchDir) {
+	for _, dir := range watchDirs {
+		target := filepath.ToSlash(dir.Path)
+		if err := h.scanQueue.Touch(string(service), target); err != nil {
+			slog.Error("failed to queue post-recovery rescan", "service", service, "path", target, "error", err)
+			continue
+		}
+		slog.Info("queued full rescan after circuit breaker recovery", "service", service, "path", target)
+	}
+}
+
 func (h *Handler) handleAbsUpdate(e fs_watcher.Event) {
-	logger := slog.With("path", e.Path, "service", types.ServiceAudiobookshelf)
+	log := logger.With(logger.SubsystemAbs, "path", e.Path, "service", types.ServiceAudiobookshelf)
 
-	if !validateEventAndExtension(e, h.allowedExtensions, logger) {
+	if !validateEventAndExtension(e, h.allowedExtensions, log) {
 		return
 	}
 
 	if h.abs == nil {
-		logger.Warn("audiobookshelf scanner not initialized, skipping event")
+		log.Warn("audiobookshelf scanner not initialized, skipping event")
 		return
 	}
 
 	eventType := getEventType(e.Op)
 	targetDir := filepath.ToSlash(filepath.Dir(e.Path))
-	logger.Debug("file event detected, queuing scan", "scan_target", targetDir, "event", eventType)
+	logger.Trace(logger.SubsystemAbs, "file event detected, queuing scan", "path", e.Path, "scan_target", targetDir, "event", eventType, "queue_depth", h.scanQueue.DepthByService()[string(types.ServiceAudiobookshelf)])
+	h.bus.Publish(eventbus.Event{Kind: eventbus.KindFsEvent, Service: string(types.ServiceAudiobookshelf), Path: e.Path})
 
-	// Check if this path is already being scanned (deduplication)
-	h.activeScansMutex.Lock()
-	if h.activeScans[targetDir] {
-		h.activeScansMutex.Unlock()
+	if err := h.scanQueue.Touch(string(types.ServiceAudiobookshelf), targetDir); err != nil {
+		log.Error("failed to queue scan", "scan_target", targetDir, "error", err)
+	}
+}
+
+func (h *Handler) handleJellyfinUpdate(e fs_watcher.Event) {
+	log := logger.With(logger.SubsystemJellyfin, "path", e.Path, "service", types.ServiceJellyfin)
+
+	if !validateEventAndExtension(e, h.allowedExtensions, log) {
 		return
 	}
-	// Mark this path as being scanned
-	h.activeScans[targetDir] = true
-	h.activeScansMutex.Unlock()
-
-	// trigger abs scan
-	go func(path string) {
-		h.scanSemaphore <- struct{}{}        // acquire a token
-		defer func() { <-h.scanSemaphore }() // release the token
-		if err := h.abs.ScanPath(h.Context, path); err != nil {
-			slog.Error("audiobookshelf scan failed", "path", path, "error", err)
-		} else {
-			slog.Info("audiobookshelf scan succeeded", "path", path)
-		}
-	}(targetDir)
+
+	if h.jf == nil {
+		log.Warn("jellyfin library manager not initialized, skipping event")
+		return
+	}
+
+	eventType := getEventType(e.Op)
+	targetDir := filepath.ToSlash(filepath.Dir(e.Path))
+	logger.Trace(logger.SubsystemJellyfin, "file event detected, queuing scan", "path", e.Path, "scan_target", targetDir, "event", eventType, "queue_depth", h.scanQueue.DepthByService()[string(types.ServiceJellyfin)])
+	h.bus.Publish(eventbus.Event{Kind: eventbus.KindFsEvent, Service: string(types.ServiceJellyfin), Path: e.Path})
+
+	if err := h.scanQueue.Touch(string(types.ServiceJellyfin), targetDir); err != nil {
+		log.Error("failed to queue scan", "scan_target", targetDir, "error", err)
+	}
 }
 
 func (h *Handler) handlePlexUpdate(e fs_watcher.Event) {
-	logger := slog.With("path", e.Path, "service", types.ServicePlex)
+	log := logger.With(logger.SubsystemPlex, "path", e.Path, "service", types.ServicePlex)
 
-	if !validateEventAndExtension(e, h.allowedExtensions, logger) {
+	if !validateEventAndExtension(e, h.allowedExtensions, log) {
 		return
 	}
 
 	if h.plex == nil {
-		logger.Warn("scanner not initialized, skipping event")
+		log.Warn("scanner not initialized, skipping event")
 		return
 	}
 
 	eventType := getEventType(e.Op)
+	isDeleted := e.Op&(fsnotify.Remove|fsnotify.Rename) != 0
 
-	// First, map to Plex path to get section info
-	_, section := h.plex.MapToPlexPath(e.Path)
-	if section == nil {
-		logger.Warn("path does not map to any Plex library path, skipping scan")
+	// First, determine the media type for the section this path belongs to
+	mediaType, err := h.plex.GetMediaType(e.Path, isDeleted)
+	if err != nil {
+		log.Warn("path does not map to any Plex library path, skipping scan", "error", err)
 		return
 	}
 
 	// Calculate scan target on LOCAL path first (like Python does)
 	// This gets us to the item root (movie folder or show folder)
-	localScanTarget := h.plex.GetScanPath(e.Path, section.SectionType)
+	localScanTarget := h.plex.GetScanPath(e.Path, mediaType)
 
 	// Now map the calculated target to Plex path
-	plexScanTarget, mappedSection := h.plex.MapToPlexPath(localScanTarget)
-	if mappedSection == nil || plexScanTarget == "" {
-		logger.Warn("failed to map scan target to Plex path, skipping scan",
+	plexScanTarget, _, ok := h.plex.MapToPlexPath(localScanTarget)
+	if !ok || plexScanTarget == "" {
+		log.Warn("failed to map scan target to Plex path, skipping scan",
 			"local_scan_target", localScanTarget)
 		return
 	}
 	targetDir := filepath.ToSlash(plexScanTarget) // normalize to forward slashes for Plex
 
-	logger.Debug("file event detected, queuing scan", "scan_target", targetDir, "event", eventType)
+	logger.Trace(logger.SubsystemPlex, "file event detected, queuing scan", "path", e.Path, "scan_target", targetDir, "event", eventType, "queue_depth", h.scanQueue.DepthByService()[string(types.ServicePlex)])
+	h.bus.Publish(eventbus.Event{Kind: eventbus.KindFsEvent, Service: string(types.ServicePlex), Path: e.Path})
 
-	// Check if this path is already being scanned (deduplication)
-	h.activeScansMutex.Lock()
-	if h.activeScans[targetDir] {
-		h.activeScansMutex.Unlock()
-		return
+	if err := h.scanQueue.Touch(string(types.ServicePlex), targetDir); err != nil {
+		log.Error("failed to queue scan", "scan_target", targetDir, "error", err)
 	}
-	// Mark this path as being scanned
-	h.activeScans[targetDir] = true
-	h.activeScansMutex.Unlock()
-
-	// trigger plex scan
-	go func(p string) {
-		h.scanSemaphore <- struct{}{}        // acquire a token
-		defer func() { <-h.scanSemaphore }() // release the token
-
-		if section, err := h.plex.ScanPath(h.Context, p); err != nil {
-			slog.Error("scan failed", "scan_target", targetDir, "error", err)
-		} else {
-			slog.Info("scan triggered", "scan_target", targetDir, "section", section.SectionTitle)
-		}
-
-		// Remove from active scans when done
-		h.activeScansMutex.Lock()
-		delete(h.activeScans, p)
-		h.activeScansMutex.Unlock()
-	}(targetDir)
 }
@@ -3,13 +3,13 @@ package api
 import (
 	"log/slog"
 	"net/http"
-	"plexwatcher/internal/http/response"
+	"plexwatcher/internal/response"
 	"plexwatcher/internal/types"
 )
 
 // status returns the current status of the watcher
 func (h *Handler) status(w http.ResponseWriter, r *http.Request) {
-	running, paths, cooldown := h.Watcher.Status()
+	running, paths, cooldown, progress := h.Watcher.Status()
 	status := "stopped"
 	if running {
 		status = "running"
@@ -24,15 +24,17 @@ func (h *Handler) status(w http.ResponseWriter, r *http.Request) {
 
 	var serverURL *string
 	if h.plex != nil {
-		url := h.plex.GetPlexClient().BaseURL.String()
+		url := h.plexServerURL
 		serverURL = &url
 	}
 
 	resp := types.StatusResponse{
 		IsWatching: running,
-		Paths:      paths,
+		WatchDirs:  paths,
 		Server:     serverURL,
 		Cooldown:   cooldown,
+		Breakers:   h.Watcher.BreakerStatus(),
+		Progress:   progress,
 	}
 	response.WriteSuccess(w, "success retrieving status", resp, http.StatusOK)
 }
@@ -3,7 +3,7 @@ package api
 import (
 	"log/slog"
 	"net/http"
-	"plexwatcher/internal/http/response"
+	"plexwatcher/internal/response"
 )
 
 // stop the watcher
@@ -13,6 +13,9 @@ func (h *Handler) stop(w http.ResponseWriter, r *http.Request) {
 		slog.Error("failed to stop Plex watcher", "error", err)
 		return
 	}
+	if h.workerCancel != nil {
+		h.workerCancel()
+	}
 	slog.Info("plex watcher stopped.")
 	response.WriteSuccess(w, "watcher stopped", nil, http.StatusOK)
 }
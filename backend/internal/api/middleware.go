@@ -1,13 +1,15 @@
 package api
 
 import (
-	"log/slog"
 	"net/http"
-	"plexwatcher/internal/http/response"
+	"plexwatcher/internal/response"
+	"plexwatcher/pkg/logger"
 )
 
 // WithCORS adds CORS headers to all responses
 func WithCORS(next http.Handler, allowedOrigins []string) http.Handler {
+	log := logger.For(logger.SubsystemAPI)
+
 	// efficient lookup table, otherwise array is fine
 	allowedOriginsMap := make(map[string]bool)
 	for _, origin := range allowedOrigins {
@@ -26,10 +28,11 @@ func WithCORS(next http.Handler, allowedOrigins []string) http.Handler {
 		}
 
 		if !allowedOriginsMap[origin] && !allowedOriginsMap["*"] { // '*' allows all
-			slog.Warn("Origin not allowed", "origin", origin)
+			log.Warn("Origin not allowed", "origin", origin)
 			response.WriteError(w, "origin not allowed", http.StatusForbidden)
 			return
 		}
+		logger.Trace(logger.SubsystemAPI, "request", "method", r.Method, "path", r.URL.Path, "origin", origin)
 
 		w.Header().Set("Access-Control-Allow-Origin", origin)
 		w.Header().Add("Vary", "Origin")
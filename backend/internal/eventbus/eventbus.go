@@ -0,0 +1,175 @@
+// Package eventbus fans out watcher/scan activity to interested subscribers
+// (currently the SSE /events endpoint) without coupling publishers to any
+// particular transport.
+package eventbus
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Kind identifies what an Event describes.
+type Kind string
+
+const (
+	KindFsEvent      Kind = "fs_event"      // raw fsnotify event observed
+	KindDebounce     Kind = "debounce"      // debounce window flushed/coalesced an event
+	KindScanDispatch Kind = "scan_dispatch" // a scan was handed off to a service
+	KindScanResult   Kind = "scan_result"   // a scan finished (success or failure)
+)
+
+// Event is one entry in the activity stream. Fields are optional depending
+// on Kind; zero values are omitted by the SSE handler's JSON encoding.
+type Event struct {
+	Id        uint64 `json:"id"`
+	Kind      Kind   `json:"kind"`
+	Service   string `json:"service,omitempty"` // plex, audiobookshelf, jellyfin
+	Path      string `json:"path,omitempty"`
+	Target    string `json:"target,omitempty"`  // resolved scan target directory, as sent to the service
+	Library   string `json:"library,omitempty"` // library/section name or id
+	HTTPCode  int    `json:"http_code,omitempty"`
+	LatencyMs int64  `json:"latency_ms,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ringSize bounds how many events a slow subscriber can fall behind by
+// before we start dropping its oldest buffered events.
+const ringSize = 256
+
+// subscriber is a bounded ring buffer plus a channel used to wake its
+// reader. Publish never blocks on a slow subscriber: on a full ring it
+// overwrites the oldest entry instead.
+type subscriber struct {
+	mu     sync.Mutex
+	ring   [ringSize]Event
+	head   int // index of the oldest valid entry
+	count  int
+	notify chan struct{}
+	closed bool
+}
+
+func newSubscriber() *subscriber {
+	return &subscriber{notify: make(chan struct{}, 1)}
+}
+
+func (s *subscriber) push(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	idx := (s.head + s.count) % ringSize
+	s.ring[idx] = e
+	if s.count < ringSize {
+		s.count++
+	} else {
+		s.head = (s.head + 1) % ringSize // drop-oldest
+	}
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// drain returns all buffered events since the last drain, oldest first.
+func (s *subscriber) drain() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Event, s.count)
+	for i := 0; i < s.count; i++ {
+		out[i] = s.ring[(s.head+i)%ringSize]
+	}
+	s.head = 0
+	s.count = 0
+	return out
+}
+
+func (s *subscriber) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.notify)
+	}
+}
+
+// Broker fans out published events to all current subscribers.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	nextId      uint64
+
+	// replay is a small shared ring of recently published events, used to
+	// serve Last-Event-ID reconnects for subscribers that joined late.
+	replayMu sync.Mutex
+	replay   [ringSize]Event
+	replayN  int
+}
+
+// NewBroker creates an empty Broker.
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[*subscriber]struct{})}
+}
+
+// Publish assigns Event its id and fans it out to all current subscribers
+// and the replay buffer. Never blocks.
+func (b *Broker) Publish(e Event) Event {
+	e.Id = atomic.AddUint64(&b.nextId, 1)
+
+	b.replayMu.Lock()
+	b.replay[int(e.Id-1)%ringSize] = e
+	b.replayN++
+	b.replayMu.Unlock()
+
+	b.mu.Lock()
+	subs := make([]*subscriber, 0, len(b.subscribers))
+	for s := range b.subscribers {
+		subs = append(subs, s)
+	}
+	b.mu.Unlock()
+
+	for _, s := range subs {
+		s.push(e)
+	}
+	return e
+}
+
+// Subscribe registers a new subscriber and returns a channel that's signaled
+// whenever new events are available; call drain to collect them. If
+// lastEventId is non-zero, replayable events after it are delivered first.
+func (b *Broker) Subscribe(lastEventId uint64) (events <-chan struct{}, drain func() []Event, unsubscribe func()) {
+	s := newSubscriber()
+
+	if lastEventId > 0 {
+		for _, e := range b.replaySince(lastEventId) {
+			s.push(e)
+		}
+	}
+
+	b.mu.Lock()
+	b.subscribers[s] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe = func() {
+		b.mu.Lock()
+		delete(b.subscribers, s)
+		b.mu.Unlock()
+		s.close()
+	}
+	return s.notify, s.drain, unsubscribe
+}
+
+func (b *Broker) replaySince(lastEventId uint64) []Event {
+	b.replayMu.Lock()
+	defer b.replayMu.Unlock()
+
+	var out []Event
+	for _, e := range b.replay {
+		if e.Id > lastEventId {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Id < out[j].Id })
+	return out
+}
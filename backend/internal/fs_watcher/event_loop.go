@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+
+	"plexwatcher/pkg/logger"
 )
 
 // eventLoop pumps events/errors, does optional debouncing, and handles recursive add-on-new-dir.
@@ -17,17 +19,16 @@ func (pw *FsWatcher) eventLoop(ctx context.Context) {
 		debounce = pw.cfg.DebounceWindow
 		timer    *time.Timer
 		pending  = make(map[string]fsnotify.Op) // path -> accumulated ops
+		settler  = newSettleTracker(pw.cfg, pw.cfg.Handler)
 	)
 
 	flush := func() {
 		if len(pending) == 0 {
 			return
 		}
+		logger.Trace(logger.SubsystemFS, "flushing debounced events", "pending", pending)
 		for p, op := range pending {
-			pw.cfg.Handler(Event{
-				Path: p,
-				Op:   op,
-			})
+			settler.dispatch(p, op)
 		}
 		pending = make(map[string]fsnotify.Op)
 	}
@@ -53,6 +54,7 @@ func (pw *FsWatcher) eventLoop(ctx context.Context) {
 				}
 			}
 			flush()
+			settler.shutdown()
 			return
 		case <-ctx.Done():
 			// Stop timer and do final flush
@@ -65,6 +67,7 @@ func (pw *FsWatcher) eventLoop(ctx context.Context) {
 				}
 			}
 			flush()
+			settler.shutdown()
 			return
 		case err, ok := <-pw.watcher.Errors:
 			if !ok {
@@ -78,14 +81,14 @@ func (pw *FsWatcher) eventLoop(ctx context.Context) {
 
 			if pw.cfg.Recursive && event.Op&fsnotify.Create == fsnotify.Create {
 				if isDir(event.Name) {
-					if err := pw.watchSubtree(event.Name); err != nil {
+					if err := pw.watchSubtree(pw.currentGeneration(), event.Name); err != nil {
 						slog.Error("failed to add new subdir", "path", event.Name, "error", err)
 					}
 				}
 			}
 
 			if debounce <= 0 {
-				pw.cfg.Handler(Event{Path: event.Name, Op: event.Op})
+				settler.dispatch(event.Name, event.Op)
 				continue
 			}
 
@@ -34,6 +34,38 @@ type Config struct {
 	// Set to 0 to disable debounce
 	DebounceWindow time.Duration
 
+	// QuietPeriod gates Create/Write events behind a per-path settle check:
+	// a path isn't dispatched until it's been quiet for at least this long
+	// and (for files) its size and mtime have been stable across
+	// StableChecks consecutive polls (two stats with the default of 1).
+	// This protects against download clients (Transmission,
+	// qBittorrent, SABnzbd) that create a directory and then trickle large
+	// files into it over many seconds - without it, a debounce flush can
+	// fire mid-move and trigger a scan of a partial tree. Set to 0 to
+	// disable the gate (events dispatch as soon as DebounceWindow flushes
+	// them). Remove events always bypass the gate.
+	QuietPeriod time.Duration
+
+	// StabilityPollInterval is the spacing between the two stat() calls
+	// used to confirm a file's size has stopped changing. Defaults to 1s
+	// if unset while QuietPeriod > 0.
+	StabilityPollInterval time.Duration
+
+	// MaxSettleWait is the hard deadline on the settle gate: a path is
+	// dispatched even if it never stabilizes once this much time has
+	// passed since its first event. 0 means wait indefinitely.
+	MaxSettleWait time.Duration
+
+	// StableChecks is how many consecutive stability polls (spaced
+	// StabilityPollInterval apart) a file's size and mtime must be
+	// unchanged across before it's considered settled. Defaults to 1 if
+	// unset while QuietPeriod > 0.
+	//
+	// Note: fsnotify (the backend this watcher uses) doesn't expose
+	// Linux's IN_CLOSE_WRITE distinctly from Create/Write, so there's no
+	// op to substitute for polling here - see Event.Op.
+	StableChecks int
+
 	// Hnadler receives events. Must be non-nil.
 	Handler Handler
 }
@@ -48,6 +80,18 @@ type FsWatcher struct {
 
 	waitGroup sync.WaitGroup
 	stop      chan struct{} // closed to signal shutdown
+
+	// progress, pendingRoots, ready, and generation track background
+	// recursive watch setup - see progress.go. ready is closed once every
+	// root in the current generation's pass has finished (or immediately,
+	// if the pass has no roots to wait on). generation is bumped by every
+	// initProgress call so a still-running pass superseded by a newer one
+	// (e.g. Start's initial setup still in flight when a Reconfigure lands)
+	// can't corrupt the new pass's bookkeeping.
+	progress     types.RecursiveProgress
+	pendingRoots int
+	ready        chan struct{}
+	generation   int
 }
 
 // Create a new watcher. Call Start(ctx) to start watching.
@@ -66,6 +110,7 @@ func NewPlexWatcher(cfg Config) (*FsWatcher, error) {
 		cfg:     cfg,
 		watcher: watcher,
 		stop:    make(chan struct{}),
+		ready:   make(chan struct{}),
 	}
 	return pw, nil
 }
@@ -117,14 +162,19 @@ func (pw *FsWatcher) Start(ctx context.Context) error {
 
 	// launch background goroutine to add subdirs if Recursive is set
 	if pw.cfg.Recursive {
+		var roots []string
 		for _, dir := range pw.cfg.Dirs {
-			if !dir.Enabled {
-				continue
+			if dir.Enabled {
+				roots = append(roots, dir.Path)
 			}
+		}
+		gen := pw.initProgress(roots)
+
+		for _, root := range roots {
 			go func(dirToScan string) {
 				slog.Info("starting recursive directory watch setup in background", "path", dirToScan)
 				startTime := time.Now()
-				if err := pw.watchSubtree(dirToScan); err != nil {
+				if err := pw.watchSubtree(gen, dirToScan); err != nil {
 					slog.Error("failed to perform recursive watch setup", "path", dirToScan, "error", err)
 				} else {
 					elapsed := time.Since(startTime)
@@ -132,9 +182,12 @@ func (pw *FsWatcher) Start(ctx context.Context) error {
 						"path", dirToScan,
 						"elapsed", elapsed.String())
 				}
-			}(dir.Path)
+				pw.rootDone(gen, dirToScan)
+			}(root)
 		}
 		slog.Info("recursive watching initiated in background - watcher is ready")
+	} else {
+		pw.initProgress(nil)
 	}
 
 	return nil
@@ -0,0 +1,137 @@
+package fs_watcher
+
+import (
+	"context"
+	"plexwatcher/internal/types"
+	"time"
+)
+
+// initProgress starts a new recursive setup pass (generation) across roots,
+// replacing pw.ready with a fresh channel that closes once every root in
+// this pass has finished (immediately, if roots is empty). It returns the
+// generation number callers must pass to setRootTotal/addProgress/rootDone
+// so that a pass superseded by a later initProgress (e.g. a Reconfigure
+// landing while Start's initial setup is still running) can't corrupt the
+// new pass's pendingRoots count or double-close pw.ready. Callers must
+// hold pw.mutex.
+func (pw *FsWatcher) initProgress(roots []string) int {
+	pw.generation++
+	gen := pw.generation
+
+	pw.progress = types.RecursiveProgress{
+		StartedAt: time.Now(),
+		PerRoot:   make(map[string]types.RootProgress, len(roots)),
+	}
+	for _, root := range roots {
+		pw.progress.PerRoot[root] = types.RootProgress{StartedAt: pw.progress.StartedAt}
+	}
+	pw.pendingRoots = len(roots)
+	pw.ready = make(chan struct{})
+	if pw.pendingRoots == 0 {
+		close(pw.ready)
+	}
+	return gen
+}
+
+// currentGeneration returns the generation of the in-progress (or most
+// recently completed) recursive setup pass, for callers that add a single
+// watch outside of any pass - e.g. eventLoop's on-the-fly watchSubtree call
+// for a newly created directory - and want their progress updates
+// attributed to it without participating in its pendingRoots count.
+func (pw *FsWatcher) currentGeneration() int {
+	pw.mutex.Lock()
+	defer pw.mutex.Unlock()
+	return pw.generation
+}
+
+// setRootTotal records how many subdirectories were discovered under root,
+// once watchSubtree has finished walking it. A gen from a pass that's since
+// been superseded by a newer initProgress call is a no-op.
+func (pw *FsWatcher) setRootTotal(gen int, root string, total int) {
+	pw.mutex.Lock()
+	defer pw.mutex.Unlock()
+	if gen != pw.generation {
+		return
+	}
+
+	rp := pw.progress.PerRoot[root]
+	rp.TotalDirs = total
+	pw.progress.PerRoot[root] = rp
+	pw.progress.TotalDirs += total
+}
+
+// addProgress records that n more subdirectories under root now have a
+// fsnotify watch. A gen from a superseded pass is a no-op.
+func (pw *FsWatcher) addProgress(gen int, root string, n int) {
+	if n == 0 {
+		return
+	}
+	pw.mutex.Lock()
+	defer pw.mutex.Unlock()
+	if gen != pw.generation {
+		return
+	}
+
+	rp := pw.progress.PerRoot[root]
+	rp.AddedDirs += n
+	pw.progress.PerRoot[root] = rp
+	pw.progress.AddedDirs += n
+}
+
+// rootDone marks root's recursive setup as finished and, once every root
+// started by gen's initProgress call has finished, closes pw.ready so
+// WaitReady callers unblock. A gen from a pass that's since been superseded
+// by a newer initProgress call is a no-op - its own rootDone calls already
+// decremented the new pass's pendingRoots from zero, which would otherwise
+// close the new pw.ready a second time.
+func (pw *FsWatcher) rootDone(gen int, root string) {
+	pw.mutex.Lock()
+	defer pw.mutex.Unlock()
+	if gen != pw.generation {
+		return
+	}
+
+	now := time.Now()
+	rp := pw.progress.PerRoot[root]
+	rp.CompletedAt = now
+	pw.progress.PerRoot[root] = rp
+
+	pw.pendingRoots--
+	if pw.pendingRoots <= 0 {
+		pw.progress.CompletedAt = now
+		close(pw.ready)
+	}
+}
+
+// Progress returns a snapshot of recursive watch setup for the most recent
+// setup pass: every enabled root for Start, or only the newly added roots
+// for an UpdateDirs call that added any. An UpdateDirs call that adds no
+// roots leaves the previous pass's snapshot in place.
+func (pw *FsWatcher) Progress() types.RecursiveProgress {
+	pw.mutex.Lock()
+	defer pw.mutex.Unlock()
+
+	snapshot := pw.progress
+	snapshot.PerRoot = make(map[string]types.RootProgress, len(pw.progress.PerRoot))
+	for root, rp := range pw.progress.PerRoot {
+		snapshot.PerRoot[root] = rp
+	}
+	return snapshot
+}
+
+// WaitReady blocks until every root of the most recent setup pass (see
+// Progress) has completed its recursive watch setup, or ctx is cancelled
+// first. Roots of an earlier pass that a later UpdateDirs superseded are
+// not waited on.
+func (pw *FsWatcher) WaitReady(ctx context.Context) error {
+	pw.mutex.Lock()
+	ready := pw.ready
+	pw.mutex.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,95 @@
+package fs_watcher
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"plexwatcher/internal/types"
+	"strings"
+)
+
+// UpdateDirs diffs dirs against the currently configured set: dropped or
+// newly-disabled directories (and every subdirectory fsnotify still has
+// watched beneath them) are removed, newly added or newly-enabled ones get
+// fresh watches (recursively if recursive is set), and directories present
+// in both sets are left untouched so their in-flight debounce/settle state
+// survives. The watcher must already be running.
+func (pw *FsWatcher) UpdateDirs(dirs []types.WatchDir, recursive bool) error {
+	pw.mutex.Lock()
+	defer pw.mutex.Unlock()
+
+	if pw.closed {
+		return errors.New("watcher already closed")
+	}
+	if !pw.started {
+		return errors.New("watcher not started")
+	}
+
+	oldEnabled := enabledPaths(pw.cfg.Dirs)
+	newEnabled := enabledPaths(dirs)
+
+	for path := range oldEnabled {
+		if newEnabled[path] {
+			continue
+		}
+		pw.removeSubtree(path)
+	}
+
+	var newRoots []string
+	for _, dir := range dirs {
+		if !dir.Enabled || oldEnabled[dir.Path] {
+			continue
+		}
+		if err := ensureDirExists(dir.Path); err != nil {
+			return err
+		}
+		if err := pw.watcher.Add(dir.Path); err != nil {
+			return fmt.Errorf("watcher.Add(%s): %w", dir.Path, err)
+		}
+		if recursive {
+			newRoots = append(newRoots, dir.Path)
+		}
+	}
+
+	if recursive && len(newRoots) > 0 {
+		gen := pw.initProgress(newRoots)
+		for _, root := range newRoots {
+			go func(root string) {
+				if err := pw.watchSubtree(gen, root); err != nil {
+					slog.Error("failed to perform recursive watch setup for reconfigured dir", "path", root, "error", err)
+				}
+				pw.rootDone(gen, root)
+			}(root)
+		}
+	}
+
+	pw.cfg.Dirs = dirs
+	pw.cfg.Recursive = recursive
+	return nil
+}
+
+// removeSubtree removes root and every path fsnotify still has watched
+// beneath it. Paths fsnotify no longer tracks are silently skipped.
+func (pw *FsWatcher) removeSubtree(root string) {
+	prefix := root + string(filepath.Separator)
+	for _, watched := range pw.watcher.WatchList() {
+		if watched != root && !strings.HasPrefix(watched, prefix) {
+			continue
+		}
+		if err := pw.watcher.Remove(watched); err != nil {
+			slog.Debug("failed to remove watch during reconfigure", "path", watched, "error", err)
+		}
+	}
+}
+
+// enabledPaths returns the set of paths among dirs that are enabled.
+func enabledPaths(dirs []types.WatchDir) map[string]bool {
+	out := make(map[string]bool, len(dirs))
+	for _, d := range dirs {
+		if d.Enabled {
+			out[d.Path] = true
+		}
+	}
+	return out
+}
@@ -1,21 +1,25 @@
 package fs_watcher
 
 import (
-	"log/slog"
 	"os"
 	"path/filepath"
+	"plexwatcher/pkg/logger"
 	"sync"
 	"sync/atomic"
 )
 
 // add subdirs recursivesly with a root path
-// Uses parallel directory traversal for better performance on large directory trees
-func (pw *FsWatcher) watchSubtree(root string) error {
+// Uses parallel directory traversal for better performance on large directory trees.
+// gen ties this call's progress updates to the initProgress pass that
+// launched it - see progress.go.
+func (pw *FsWatcher) watchSubtree(gen int, root string) error {
+	log := logger.For(logger.SubsystemRecursive)
+
 	// Collect all directories first (fast - just filesystem scan)
 	var dirs []string
 	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
-			slog.Debug("error accessing path during scan, skipping", "path", path, "error", err)
+			logger.Trace(logger.SubsystemRecursive, "error accessing path during scan, skipping", "path", path, "error", err)
 			if d != nil && d.IsDir() {
 				return filepath.SkipDir
 			}
@@ -30,7 +34,8 @@ func (pw *FsWatcher) watchSubtree(root string) error {
 		return err
 	}
 
-	slog.Debug("directories discovered, adding watches", "count", len(dirs), "root", root)
+	logger.Trace(logger.SubsystemRecursive, "directories discovered, adding watches", "count", len(dirs), "root", root)
+	pw.setRootTotal(gen, root, len(dirs))
 
 	// add watches in parallel (slow - syscalls)
 	var wg sync.WaitGroup
@@ -58,7 +63,7 @@ func (pw *FsWatcher) watchSubtree(root string) error {
 			localFailed := 0
 			for _, dir := range chunk {
 				if err := pw.watcher.Add(dir); err != nil {
-					slog.Debug("failed to add watch", "path", dir, "error", err)
+					logger.Trace(logger.SubsystemRecursive, "failed to add watch", "path", dir, "error", err)
 					localFailed++
 				} else {
 					localAdded++
@@ -66,15 +71,16 @@ func (pw *FsWatcher) watchSubtree(root string) error {
 			}
 			atomic.AddInt64(&addedCount, int64(localAdded))
 			atomic.AddInt64(&failCount, int64(localFailed))
+			pw.addProgress(gen, root, localAdded)
 		}(dirs[start:end])
 	}
 
 	wg.Wait()
 
 	if failCount > 0 {
-		slog.Warn("some watches failed to add", "added", addedCount, "failed", failCount, "root", root)
+		log.Warn("some watches failed to add", "added", addedCount, "failed", failCount, "root", root)
 	} else {
-		slog.Debug("completed adding watches", "directories", addedCount, "root", root)
+		logger.Trace(logger.SubsystemRecursive, "completed adding watches", "directories", addedCount, "root", root)
 	}
 
 	return nil
@@ -0,0 +1,208 @@
+package fs_watcher
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// settleTracker gates Create/Write events behind a per-path quiet period
+// plus a file-size stability check before handing them to Handler, so a
+// debounce flush mid-move doesn't trigger a scan of a partial tree. Remove
+// events always bypass the gate - see dispatch.
+type settleTracker struct {
+	cfg     Config
+	handler Handler
+
+	mu      sync.Mutex
+	pending map[string]*settleEntry
+	cancel  chan struct{}
+
+	wg sync.WaitGroup
+}
+
+type settleEntry struct {
+	op         fsnotify.Op
+	lastChange time.Time
+	deadline   time.Time
+}
+
+func newSettleTracker(cfg Config, handler Handler) *settleTracker {
+	return &settleTracker{
+		cfg:     cfg,
+		handler: handler,
+		pending: make(map[string]*settleEntry),
+		cancel:  make(chan struct{}),
+	}
+}
+
+// enabled reports whether the gate does anything; QuietPeriod <= 0 disables
+// it, matching DebounceWindow's "0 disables" convention.
+func (t *settleTracker) enabled() bool {
+	return t.cfg.QuietPeriod > 0
+}
+
+// dispatch routes one flushed (path, op) pair either straight to Handler
+// (Remove events, or when the gate is disabled) or into the settle gate.
+func (t *settleTracker) dispatch(path string, op fsnotify.Op) {
+	if op&fsnotify.Remove == fsnotify.Remove || !t.enabled() {
+		t.handler(Event{Path: path, Op: op})
+		return
+	}
+
+	now := time.Now()
+	t.mu.Lock()
+	if entry, exists := t.pending[path]; exists {
+		// A Rename->Create (or any repeat event) for a path already being
+		// settled extends the existing wait instead of starting a second
+		// one, so it collapses into a single post-settle dispatch.
+		entry.op |= op
+		entry.lastChange = now
+		t.mu.Unlock()
+		return
+	}
+	entry := &settleEntry{op: op, lastChange: now}
+	if t.cfg.MaxSettleWait > 0 {
+		entry.deadline = now.Add(t.cfg.MaxSettleWait)
+	}
+	t.pending[path] = entry
+	t.mu.Unlock()
+
+	t.wg.Add(1)
+	go t.settle(path, entry)
+}
+
+// settle polls path until it's been quiet for QuietPeriod and passed the
+// stability check, or MaxSettleWait elapses, then dispatches the collapsed
+// event to Handler.
+func (t *settleTracker) settle(path string, entry *settleEntry) {
+	defer t.wg.Done()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, path)
+		t.mu.Unlock()
+	}()
+
+	poll := t.cfg.StabilityPollInterval
+	if poll <= 0 {
+		poll = time.Second
+	}
+	stableChecks := t.cfg.StableChecks
+	if stableChecks < 1 {
+		stableChecks = 1
+	}
+	hasDeadline := t.cfg.MaxSettleWait > 0
+
+	for {
+		t.mu.Lock()
+		lastChange := entry.lastChange
+		deadline := entry.deadline
+		op := entry.op
+		t.mu.Unlock()
+
+		if hasDeadline && !time.Now().Before(deadline) {
+			t.handler(Event{Path: path, Op: op})
+			return
+		}
+
+		if quiet := time.Since(lastChange); quiet < t.cfg.QuietPeriod {
+			if !t.sleep(t.cfg.QuietPeriod-quiet, deadline, hasDeadline) {
+				t.handler(Event{Path: path, Op: op}) // cancelled (shutdown): flush now
+				return
+			}
+			continue
+		}
+
+		if isPathStable(path, poll, stableChecks) {
+			t.handler(Event{Path: path, Op: op})
+			return
+		}
+		if !t.sleep(poll, deadline, hasDeadline) {
+			t.handler(Event{Path: path, Op: op})
+			return
+		}
+	}
+}
+
+// sleep waits for d (capped to deadline when hasDeadline), returning false
+// if the tracker was shut down mid-sleep so the caller dispatches
+// immediately instead of continuing to wait.
+func (t *settleTracker) sleep(d time.Duration, deadline time.Time, hasDeadline bool) bool {
+	if hasDeadline {
+		if remaining := time.Until(deadline); d > remaining {
+			d = remaining
+		}
+	}
+	if d <= 0 {
+		return true
+	}
+	select {
+	case <-time.After(d):
+		return true
+	case <-t.cancel:
+		return false
+	}
+}
+
+// shutdown cancels every in-flight settle wait - each dispatches
+// immediately with whatever op it has accumulated so far - then blocks
+// until they've all finished.
+func (t *settleTracker) shutdown() {
+	close(t.cancel)
+	t.wg.Wait()
+}
+
+// isPathStable reports whether path (for a file) or every immediate child
+// file (for a directory; the directory itself is not compared) has an
+// unchanging size and mtime across checks consecutive polls spaced by poll,
+// i.e. checks+1 stat calls per file. Only one level of recursion is performed, per
+// spec. A path that's since disappeared counts as stable - there's nothing
+// left to wait on.
+func isPathStable(path string, poll time.Duration, checks int) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return true
+	}
+	if !info.IsDir() {
+		return fileSizeStable(path, poll, checks)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return true
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue // recurse one level only
+		}
+		if !fileSizeStable(filepath.Join(path, e.Name()), poll, checks) {
+			return false
+		}
+	}
+	return true
+}
+
+func fileSizeStable(path string, poll time.Duration, checks int) bool {
+	if checks < 1 {
+		checks = 1
+	}
+	prev, err := os.Stat(path)
+	if err != nil {
+		return true
+	}
+	for i := 0; i < checks; i++ {
+		time.Sleep(poll)
+		cur, err := os.Stat(path)
+		if err != nil {
+			return true
+		}
+		if cur.Size() != prev.Size() || !cur.ModTime().Equal(prev.ModTime()) {
+			return false
+		}
+		prev = cur
+	}
+	return true
+}
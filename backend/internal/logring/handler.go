@@ -0,0 +1,59 @@
+package logring
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Handler forwards every record to next (the process's normal output, e.g.
+// console + rotating-file) and also mirrors it into ring. Note: nested
+// WithGroup attrs are flattened into Record.Attrs by key rather than nested,
+// which is fine for the /logs endpoint's grep-and-filter use case.
+type Handler struct {
+	next  slog.Handler
+	ring  *Ring
+	attrs []slog.Attr
+}
+
+// NewHandler wraps next, mirroring every record it handles into ring.
+func NewHandler(next slog.Handler, ring *Ring) *Handler {
+	return &Handler{next: next, ring: ring}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	attrs := make(map[string]any, record.NumAttrs()+len(h.attrs))
+	for _, a := range h.attrs {
+		attrs[a.Key] = a.Value.Any()
+	}
+	record.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value.Any()
+		return true
+	})
+	if len(attrs) == 0 {
+		attrs = nil
+	}
+
+	h.ring.Append(Record{
+		Time:    record.Time,
+		Level:   record.Level.String(),
+		Message: record.Message,
+		Attrs:   attrs,
+	})
+
+	return h.next.Handle(ctx, record)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	merged := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	merged = append(merged, h.attrs...)
+	merged = append(merged, attrs...)
+	return &Handler{next: h.next.WithAttrs(attrs), ring: h.ring, attrs: merged}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name), ring: h.ring, attrs: h.attrs}
+}
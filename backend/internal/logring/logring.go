@@ -0,0 +1,142 @@
+// Package logring is an slog.Handler that mirrors every log record into an
+// in-memory segmented ring buffer, so the last N minutes of activity can be
+// served over HTTP (see internal/api's /logs and /logs/tail) without any
+// call-site changes across fs_watcher, plex, audiobookshelf, api, etc. -
+// every module already logs through slog, so wrapping the base handler once
+// at startup is enough.
+package logring
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Record is one captured log line, JSON-encodable for the /logs endpoint.
+type Record struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"msg"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// segment holds every Record observed within one bucket of segmentDur.
+type segment struct {
+	start   time.Time
+	records []Record
+}
+
+// Ring is a segmented, drop-oldest log buffer. Old segments are dropped in
+// O(1) (a slice re-slice) rather than trimming record-by-record. Readers
+// take a snapshot of the segment slice under a brief read lock and then
+// work on their own copy, so a burst of writes never blocks a reader for
+// long and vice versa.
+type Ring struct {
+	mu          sync.RWMutex
+	segmentDur  time.Duration
+	maxSegments int
+	segments    []*segment
+
+	subMu       sync.Mutex
+	subscribers map[chan Record]struct{}
+}
+
+// NewRing creates a Ring holding up to maxSegments segments of segmentDur
+// each (e.g. 60 one-minute segments keeps roughly the last hour).
+func NewRing(segmentDur time.Duration, maxSegments int) *Ring {
+	return &Ring{
+		segmentDur:  segmentDur,
+		maxSegments: maxSegments,
+		subscribers: make(map[chan Record]struct{}),
+	}
+}
+
+var defaultRing = NewRing(time.Minute, 60)
+
+// Default returns the process-wide ring. main wraps the base slog handler
+// with NewHandler(base, Default()) once at startup; everything else just
+// logs normally and this ring fills in automatically.
+func Default() *Ring {
+	return defaultRing
+}
+
+// Append records r, starting a new segment if the current one has aged out,
+// and drops the oldest segment(s) once maxSegments is exceeded.
+func (rg *Ring) Append(r Record) {
+	rg.mu.Lock()
+	bucket := r.Time.Truncate(rg.segmentDur)
+	if n := len(rg.segments); n == 0 || rg.segments[n-1].start.Before(bucket) {
+		rg.segments = append(rg.segments, &segment{start: bucket})
+	}
+	// drop segments past maxSegments, and any older than the window of
+	// maxSegments buckets ending at this record's bucket
+	cutoff := bucket.Add(-time.Duration(rg.maxSegments) * rg.segmentDur)
+	drop := len(rg.segments) - rg.maxSegments
+	if drop < 0 {
+		drop = 0
+	}
+	for drop < len(rg.segments)-1 && !rg.segments[drop].start.After(cutoff) {
+		drop++
+	}
+	rg.segments = rg.segments[drop:]
+	rg.segments[len(rg.segments)-1].records = append(rg.segments[len(rg.segments)-1].records, r)
+	rg.mu.Unlock()
+
+	rg.subMu.Lock()
+	for ch := range rg.subscribers {
+		select {
+		case ch <- r:
+		default: // slow subscriber; drop rather than stall the writer
+		}
+	}
+	rg.subMu.Unlock()
+}
+
+// Query returns every retained record at or after since (the zero value
+// matches everything) whose level is >= minLevel, oldest first.
+func (rg *Ring) Query(since time.Time, minLevel slog.Level) []Record {
+	rg.mu.RLock()
+	// copy each segment by value so its records slice header (length) is
+	// fixed; a concurrent Append only writes past that length
+	segments := make([]segment, len(rg.segments))
+	for i, seg := range rg.segments {
+		segments[i] = *seg
+	}
+	rg.mu.RUnlock()
+
+	var out []Record
+	for _, seg := range segments {
+		if !since.IsZero() && seg.start.Add(rg.segmentDur).Before(since) {
+			continue // whole segment predates since
+		}
+		for _, r := range seg.records {
+			if !since.IsZero() && r.Time.Before(since) {
+				continue
+			}
+			var lvl slog.Level
+			if err := lvl.UnmarshalText([]byte(r.Level)); err == nil && lvl < minLevel {
+				continue
+			}
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Subscribe returns a channel fed every Record appended from now on, and an
+// unsubscribe func that must be called to release it. The channel is
+// non-blocking on the writer's side: a slow /logs/tail client just misses
+// records rather than stalling logging elsewhere in the process.
+func (rg *Ring) Subscribe() (records <-chan Record, unsubscribe func()) {
+	ch := make(chan Record, 256)
+	rg.subMu.Lock()
+	rg.subscribers[ch] = struct{}{}
+	rg.subMu.Unlock()
+
+	unsubscribe = func() {
+		rg.subMu.Lock()
+		delete(rg.subscribers, ch)
+		rg.subMu.Unlock()
+	}
+	return ch, unsubscribe
+}
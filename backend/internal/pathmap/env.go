@@ -0,0 +1,36 @@
+package pathmap
+
+import (
+	"log/slog"
+	"plexwatcher/internal/types"
+	"strings"
+)
+
+// ParseEnv parses PATH_MAP, a comma-separated list of "from:to" pairs, e.g.
+// "PATH_MAP=/mnt/media/movies:/data/movies,/mnt/media/tv:/data/tv". Windows
+// paths (containing a drive-letter colon) are supported by only splitting
+// on the last colon in each pair.
+func ParseEnv(raw string) []types.PathMappingRule {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+
+	var rules []types.PathMappingRule
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		idx := strings.LastIndex(pair, ":")
+		if idx <= 0 || idx == len(pair)-1 {
+			slog.Warn("skipping malformed PATH_MAP entry, expected 'from:to'", "entry", pair)
+			continue
+		}
+		rules = append(rules, types.PathMappingRule{
+			From: strings.TrimSpace(pair[:idx]),
+			To:   strings.TrimSpace(pair[idx+1:]),
+		})
+	}
+	return rules
+}
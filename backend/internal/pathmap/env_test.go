@@ -0,0 +1,47 @@
+package pathmap
+
+import (
+	"plexwatcher/internal/types"
+	"reflect"
+	"testing"
+)
+
+func TestParseEnv(t *testing.T) {
+	got := ParseEnv("/mnt/media/movies:/data/movies,/mnt/media/tv:/data/tv")
+	want := []types.PathMappingRule{
+		{From: "/mnt/media/movies", To: "/data/movies"},
+		{From: "/mnt/media/tv", To: "/data/tv"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseEnv() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseEnvWindowsDriveLetter(t *testing.T) {
+	got := ParseEnv(`D:\Media\Movies:/movies`)
+	want := []types.PathMappingRule{
+		{From: `D:\Media\Movies`, To: "/movies"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseEnv() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseEnvSkipsMalformedEntries(t *testing.T) {
+	got := ParseEnv("no-colon-here,/mnt/media:,:/data,/mnt/media/tv:/data/tv")
+	want := []types.PathMappingRule{
+		{From: "/mnt/media/tv", To: "/data/tv"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseEnv() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseEnvEmpty(t *testing.T) {
+	if got := ParseEnv(""); got != nil {
+		t.Errorf("ParseEnv(\"\") = %+v, want nil", got)
+	}
+	if got := ParseEnv("   "); got != nil {
+		t.Errorf("ParseEnv(\"   \") = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,116 @@
+// Package pathmap applies explicit From->To path rewrite rules, for
+// deployments where the watcher and the media server don't share a
+// filesystem view (watcher on host, server in a container/remote box).
+// It's a precise alternative to the longest-suffix inference that
+// plex.Scanner and audiobookshelf.LibraryManager fall back to when no rule
+// matches.
+package pathmap
+
+import (
+	"path/filepath"
+	"plexwatcher/internal/types"
+	"sort"
+	"strings"
+)
+
+// PathMapper rewrites local paths using an ordered set of From->To rules.
+// Rules apply bidirectionally: Map goes local->remote (From prefix), and
+// MapReverse goes remote->local (To prefix), each using its own
+// longest-prefix ordering.
+type PathMapper struct {
+	rules        []types.PathMappingRule // sorted by len(From) descending
+	reverseRules []types.PathMappingRule // sorted by len(To) descending
+}
+
+// New builds a PathMapper from rules, longest-From-prefix first so more
+// specific rules win over broader ones that also match.
+func New(rules []types.PathMappingRule) *PathMapper {
+	sorted := make([]types.PathMappingRule, len(rules))
+	copy(sorted, rules)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(cleanSlash(sorted[i].From)) > len(cleanSlash(sorted[j].From))
+	})
+
+	reverseSorted := make([]types.PathMappingRule, len(rules))
+	copy(reverseSorted, rules)
+	sort.Slice(reverseSorted, func(i, j int) bool {
+		return len(cleanSlash(reverseSorted[i].To)) > len(cleanSlash(reverseSorted[j].To))
+	})
+
+	return &PathMapper{rules: sorted, reverseRules: reverseSorted}
+}
+
+// Map rewrites localPath using the first matching rule's From prefix,
+// replacing it with To. Returns ok=false if no rule's From is a prefix of
+// localPath.
+func (m *PathMapper) Map(localPath string) (mapped string, ok bool) {
+	if m == nil || len(m.rules) == 0 {
+		return "", false
+	}
+	clean := cleanSlash(localPath)
+
+	for _, rule := range m.rules {
+		from := cleanSlash(rule.From)
+		if from == "" {
+			continue
+		}
+		matchPath, matchFrom := clean, from
+		if rule.CaseInsensitive {
+			matchPath, matchFrom = strings.ToLower(clean), strings.ToLower(from)
+		}
+		if !strings.HasPrefix(matchPath, matchFrom) {
+			continue
+		}
+		// require a full path-segment match, not just a string prefix
+		rest := clean[len(from):]
+		if rest != "" && rest[0] != '/' {
+			continue
+		}
+		return filepath.ToSlash(cleanSlash(rule.To) + rest), true
+	}
+	return "", false
+}
+
+// MapReverse rewrites remotePath using the first matching rule's To prefix,
+// replacing it with From - the inverse of Map. It resolves a path the media
+// server reported back to its local equivalent, for cases like a Plex
+// section root that only exists inside Plex's container and has no
+// matching directory on the filesystem the watcher walks. Returns ok=false
+// if no rule's To is a prefix of remotePath.
+func (m *PathMapper) MapReverse(remotePath string) (mapped string, ok bool) {
+	if m == nil || len(m.reverseRules) == 0 {
+		return "", false
+	}
+	clean := cleanSlash(remotePath)
+
+	for _, rule := range m.reverseRules {
+		to := cleanSlash(rule.To)
+		if to == "" {
+			continue
+		}
+		matchPath, matchTo := clean, to
+		if rule.CaseInsensitive {
+			matchPath, matchTo = strings.ToLower(clean), strings.ToLower(to)
+		}
+		if !strings.HasPrefix(matchPath, matchTo) {
+			continue
+		}
+		// require a full path-segment match, not just a string prefix
+		rest := clean[len(to):]
+		if rest != "" && rest[0] != '/' {
+			continue
+		}
+		return filepath.ToSlash(cleanSlash(rule.From) + rest), true
+	}
+	return "", false
+}
+
+// cleanSlash normalizes path separators to '/' (so Windows-style From/To
+// rules work regardless of which OS the watcher runs on - filepath.ToSlash
+// is no help here since it only rewrites the *host* OS's separator, and a
+// backslash is just another rune on Linux) and strips any trailing slash.
+func cleanSlash(p string) string {
+	p = strings.ReplaceAll(p, `\`, "/")
+	p = strings.TrimRight(p, "/")
+	return p
+}
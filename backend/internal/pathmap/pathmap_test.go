@@ -0,0 +1,159 @@
+package pathmap
+
+import (
+	"plexwatcher/internal/types"
+	"testing"
+)
+
+func TestMapWindowsToLinux(t *testing.T) {
+	m := New([]types.PathMappingRule{
+		{From: `D:\Media\Movies`, To: "/movies"},
+	})
+
+	mapped, ok := m.Map(`D:\Media\Movies\Inception (2010)\Inception.mkv`)
+	if !ok {
+		t.Fatalf("expected a match")
+	}
+	want := "/movies/Inception (2010)/Inception.mkv"
+	if mapped != want {
+		t.Errorf("mapped = %q, want %q", mapped, want)
+	}
+}
+
+func TestMapCaseInsensitive(t *testing.T) {
+	m := New([]types.PathMappingRule{
+		{From: "/mnt/media/movies", To: "/data/movies", CaseInsensitive: true},
+	})
+
+	mapped, ok := m.Map("/MNT/Media/MOVIES/Inception")
+	if !ok {
+		t.Fatalf("expected a case-insensitive match")
+	}
+	want := "/data/movies/Inception"
+	if mapped != want {
+		t.Errorf("mapped = %q, want %q", mapped, want)
+	}
+
+	if _, ok := New([]types.PathMappingRule{
+		{From: "/mnt/media/movies", To: "/data/movies"},
+	}).Map("/MNT/Media/MOVIES/Inception"); ok {
+		t.Errorf("expected no match without CaseInsensitive")
+	}
+}
+
+func TestMapOverlappingPrefixPrecedence(t *testing.T) {
+	m := New([]types.PathMappingRule{
+		{From: "/mnt/media", To: "/data"},
+		{From: "/mnt/media/movies", To: "/movies"},
+	})
+
+	mapped, ok := m.Map("/mnt/media/movies/Inception")
+	if !ok {
+		t.Fatalf("expected a match")
+	}
+	want := "/movies/Inception"
+	if mapped != want {
+		t.Errorf("longest From prefix should win: mapped = %q, want %q", mapped, want)
+	}
+
+	// a path under the shorter rule only should still fall back to it.
+	mapped, ok = m.Map("/mnt/media/tv/Show")
+	if !ok {
+		t.Fatalf("expected a match")
+	}
+	want = "/data/tv/Show"
+	if mapped != want {
+		t.Errorf("mapped = %q, want %q", mapped, want)
+	}
+}
+
+func TestMapRequiresFullSegment(t *testing.T) {
+	m := New([]types.PathMappingRule{
+		{From: "/mnt/media", To: "/data"},
+	})
+
+	if _, ok := m.Map("/mnt/mediaX/movies"); ok {
+		t.Errorf("expected no match: /mnt/mediaX is not under /mnt/media")
+	}
+}
+
+func TestMapNoRuleMatches(t *testing.T) {
+	m := New([]types.PathMappingRule{
+		{From: "/mnt/media", To: "/data"},
+	})
+
+	if _, ok := m.Map("/other/path"); ok {
+		t.Errorf("expected no match")
+	}
+}
+
+func TestMapReverseWindowsRoundTrip(t *testing.T) {
+	m := New([]types.PathMappingRule{
+		{From: `C:\Media\TV`, To: "/data/tv"},
+	})
+
+	local, ok := m.MapReverse("/data/tv/Show S01/Show.S01E01.mkv")
+	if !ok {
+		t.Fatalf("expected a match")
+	}
+	want := "C:/Media/TV/Show S01/Show.S01E01.mkv"
+	if local != want {
+		t.Errorf("local = %q, want %q", local, want)
+	}
+
+	remote, ok := m.Map(local)
+	if !ok {
+		t.Fatalf("expected the reverse-mapped path to map forward again")
+	}
+	if want := "/data/tv/Show S01/Show.S01E01.mkv"; remote != want {
+		t.Errorf("round trip: remote = %q, want %q", remote, want)
+	}
+}
+
+func TestMapReverseOverlappingPrefixPrecedence(t *testing.T) {
+	m := New([]types.PathMappingRule{
+		{From: "/mnt/media", To: "/data"},
+		{From: "/srv/movies", To: "/data/movies"},
+	})
+
+	local, ok := m.MapReverse("/data/movies/Inception")
+	if !ok {
+		t.Fatalf("expected a match")
+	}
+	want := "/srv/movies/Inception"
+	if local != want {
+		t.Errorf("longest To prefix should win: local = %q, want %q", local, want)
+	}
+}
+
+func TestMapReverseCaseInsensitive(t *testing.T) {
+	m := New([]types.PathMappingRule{
+		{From: "/mnt/media/movies", To: "/data/movies", CaseInsensitive: true},
+	})
+
+	local, ok := m.MapReverse("/DATA/Movies/Inception")
+	if !ok {
+		t.Fatalf("expected a case-insensitive match")
+	}
+	want := "/mnt/media/movies/Inception"
+	if local != want {
+		t.Errorf("local = %q, want %q", local, want)
+	}
+}
+
+func TestMapReverseNoRuleMatches(t *testing.T) {
+	m := New([]types.PathMappingRule{
+		{From: "/mnt/media", To: "/data"},
+	})
+
+	if _, ok := m.MapReverse("/other/path"); ok {
+		t.Errorf("expected no match")
+	}
+}
+
+func TestMapReverseNilMapper(t *testing.T) {
+	var m *PathMapper
+	if _, ok := m.MapReverse("/data/movies"); ok {
+		t.Errorf("expected nil mapper to report no match")
+	}
+}
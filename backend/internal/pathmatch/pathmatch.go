@@ -0,0 +1,46 @@
+// Package pathmatch implements the longest-prefix, case-insensitive path
+// matching used by audiobookshelf.LibraryManager.GetLibraryByPath and
+// jellyfin.LibraryManager.MapToJellyfinPath to find which of a set of
+// library root paths a given filesystem path falls under.
+// plex.Scanner.findSection does not use it: it does its own case-sensitive
+// matching with filepath.Rel.
+package pathmatch
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// LongestPrefix returns the index into roots whose path is the longest
+// prefix of path, matching case-insensitively and only on full path-segment
+// boundaries (so "/media/shows-new" doesn't match root "/media/shows"). ok
+// is false if no root matches.
+func LongestPrefix(path string, roots []string) (index int, ok bool) {
+	lower := strings.ToLower(filepath.ToSlash(filepath.Clean(path)))
+
+	bestLen := -1
+	bestIndex := -1
+	for i, root := range roots {
+		if root == "" {
+			continue
+		}
+		rootLower := strings.ToLower(filepath.ToSlash(filepath.Clean(root)))
+		if !strings.HasPrefix(lower, rootLower) {
+			continue
+		}
+		isExactMatch := len(rootLower) == len(lower)
+		isSubPath := len(lower) > len(rootLower) && lower[len(rootLower)] == '/'
+		if !isExactMatch && !isSubPath {
+			continue
+		}
+		if len(rootLower) > bestLen {
+			bestLen = len(rootLower)
+			bestIndex = i
+		}
+	}
+
+	if bestIndex < 0 {
+		return 0, false
+	}
+	return bestIndex, true
+}
@@ -1,6 +1,7 @@
 package plex
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -10,10 +11,17 @@ import (
 	"path/filepath"
 	"strconv"
 	"time"
+
+	"plexwatcher/internal/types"
+	"plexwatcher/pkg/logger"
 )
 
+// traceBodyCap bounds how many bytes of a response body get copied into a
+// trace log line, so PW_TRACE=plex can't blow up memory on a huge library listing.
+const traceBodyCap = 4 << 10
+
 type PlexAPI interface {
-	ListSections(ctx context.Context) ([]SectionRoot, error)
+	ListSections(ctx context.Context) ([]types.PlexSection, error)
 	ScanSectionPath(ctx context.Context, sectionKey int, path *string) error
 }
 
@@ -77,12 +85,25 @@ func (pc *PlexClient) newRequest(ctx context.Context, method string, u *url.URL,
 	return req, nil
 }
 
+// traceResponse logs the response status and a size-capped copy of its body
+// when PW_TRACE has the plex subsystem enabled, then restores res.Body so
+// callers can still read it normally. It's a no-op (no body read) when
+// tracing is off.
+func traceResponse(res *http.Response) {
+	if !logger.Enabled(logger.SubsystemPlex) {
+		return
+	}
+	b, _ := io.ReadAll(io.LimitReader(res.Body, traceBodyCap))
+	res.Body = io.NopCloser(io.MultiReader(bytes.NewReader(b), res.Body))
+	logger.Trace(logger.SubsystemPlex, "plex api response", "status", res.StatusCode, "body", string(b))
+}
+
 // ======================
 // PUBLIC API
 // ======================
 
 // List all root libraries. (use this to get section keys for further operations)
-func (pc *PlexClient) ListSections(ctx context.Context) ([]SectionRoot, error) {
+func (pc *PlexClient) ListSections(ctx context.Context) ([]types.PlexSection, error) {
 	// ENDPOINT: /library/sections
 	u := pc.buildURL([]string{"library", "sections"}, nil)
 
@@ -90,35 +111,37 @@ func (pc *PlexClient) ListSections(ctx context.Context) ([]SectionRoot, error) {
 	if err != nil {
 		return nil, err
 	}
+	logger.Trace(logger.SubsystemPlex, "plex api request", "method", req.Method, "url", u.String())
 
 	res, err := pc.HTTP.Do(req) // <-- make request, get response
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close() // <-- finally: close body
+	traceResponse(res)
 
 	if res.StatusCode < 200 || res.StatusCode >= 300 {
 		b, _ := io.ReadAll(io.LimitReader(res.Body, 4<<10)) // <-- only return 4096 bytes of message
 		return nil, fmt.Errorf("plex list libraries: http %d: %s", res.StatusCode, string(b))
 	}
 
-	var resData ListSectionResponse // define to match plex schema
+	var resData types.PlexListSectionResponse // define to match plex schema
 	err = json.NewDecoder(res.Body).Decode(&resData)
 	if err != nil {
 		return nil, fmt.Errorf("plex list libraries: decode: %w", err)
 	}
-	sections := make([]SectionRoot, 0, len(resData.MediaContainer.Directory)) // <-- create a section root array
+	sections := make([]types.PlexSection, 0, len(resData.MediaContainer.Directory)) // <-- create a section root array
 	for _, d := range resData.MediaContainer.Directory {
 		id, err := strconv.Atoi(d.Key)
 		if err != nil {
 			return nil, fmt.Errorf("plex list library: failed to convert SectionKey to interger")
 		}
-		var mediaType MediaType
+		var mediaType types.PlexMediaType
 		switch d.Type {
 		case "movie":
-			mediaType = MediaTypeMovie
+			mediaType = types.MediaTypeMovie
 		case "show":
-			mediaType = MediaTypeShow
+			mediaType = types.MediaTypeShow
 		default:
 			return nil, fmt.Errorf("plex list library: unkown or unsupported media type: %s", d.Type)
 		}
@@ -128,7 +151,7 @@ func (pc *PlexClient) ListSections(ctx context.Context) ([]SectionRoot, error) {
 			rootPath = filepath.Clean(d.Location[0].Path)
 		}
 
-		sections = append(sections, SectionRoot{
+		sections = append(sections, types.PlexSection{
 			SectionKey:   id,
 			SectionTitle: d.Title,
 			SectionType:  mediaType,
@@ -154,12 +177,14 @@ func (pc *PlexClient) ScanSectionPath(ctx context.Context, sectionKey int, path
 	if err != nil {
 		return err
 	}
+	logger.Trace(logger.SubsystemPlex, "plex api request", "method", req.Method, "url", u.String())
 
 	res, err := pc.HTTP.Do(req)
 	if err != nil {
 		return err
 	}
 	defer res.Body.Close()
+	traceResponse(res)
 
 	if res.StatusCode < 200 || res.StatusCode >= 300 {
 		b, _ := io.ReadAll(io.LimitReader(res.Body, 4<<10)) // <-- only return 4096 bytes of message
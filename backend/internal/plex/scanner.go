@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"path/filepath"
+	"plexwatcher/internal/pathmap"
+	"plexwatcher/internal/types"
 	"sort"
 	"strings"
 	"time"
@@ -17,17 +20,24 @@ type Scanner struct {
 	api PlexAPI
 
 	// sections maps section title to section metadata
-	sections map[string]SectionRoot
+	sections map[string]types.PlexSection
 
 	// roots contains all library root paths sorted by length (longest first)
 	// This enables proper matching for nested library structures
-	roots []SectionRoot
+	roots []types.PlexSection
+
+	// pathMapper applies explicit From->To rewrite rules before falling
+	// back to suffix-based root matching. Holds no rules (and so never
+	// matches) if none were configured.
+	pathMapper *pathmap.PathMapper
 }
 
 // NewScanner creates a new Scanner instance.
 // It fetches all library sections from the Plex server and builds
-// an optimized lookup structure for path-to-section matching.
-func NewScanner(ctx context.Context, api PlexAPI) (*Scanner, error) {
+// an optimized lookup structure for path-to-section matching. pathMappings
+// may be nil; when empty, path resolution falls back entirely to
+// suffix-based root matching.
+func NewScanner(ctx context.Context, api PlexAPI, pathMappings []types.PathMappingRule) (*Scanner, error) {
 	sections, err := api.ListSections(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list libraries: %w", err)
@@ -37,13 +47,13 @@ func NewScanner(ctx context.Context, api PlexAPI) (*Scanner, error) {
 	}
 
 	// Build section map by title
-	sectionMap := make(map[string]SectionRoot)
+	sectionMap := make(map[string]types.PlexSection)
 	for _, section := range sections {
 		sectionMap[section.SectionTitle] = section
 	}
 
 	// Sort roots by path length (longest first) for proper nested matching
-	roots := make([]SectionRoot, len(sections))
+	roots := make([]types.PlexSection, len(sections))
 	copy(roots, sections)
 	sort.Slice(roots, func(i, j int) bool {
 		return len(roots[i].RootPath) > len(roots[j].RootPath)
@@ -56,9 +66,10 @@ func NewScanner(ctx context.Context, api PlexAPI) (*Scanner, error) {
 	}
 
 	return &Scanner{
-		api:      api,
-		sections: sectionMap,
-		roots:    roots,
+		api:        api,
+		sections:   sectionMap,
+		roots:      roots,
+		pathMapper: pathmap.New(pathMappings),
 	}, nil
 }
 
@@ -70,7 +81,7 @@ func NewScanner(ctx context.Context, api PlexAPI) (*Scanner, error) {
 //   - Falls back to section type detection
 //
 // For existing paths, it verifies the section type directly.
-func (s *Scanner) GetMediaType(path string, isDeleted bool) (MediaType, error) {
+func (s *Scanner) GetMediaType(path string, isDeleted bool) (types.PlexMediaType, error) {
 	// For deleted paths, use heuristic analysis
 	if isDeleted {
 		return s.getMediaTypeForDeleted(path)
@@ -87,7 +98,7 @@ func (s *Scanner) GetMediaType(path string, isDeleted bool) (MediaType, error) {
 
 // getMediaTypeForDeleted uses path structure heuristics to determine media type
 // when the file/directory no longer exists on disk.
-func (s *Scanner) getMediaTypeForDeleted(path string) (MediaType, error) {
+func (s *Scanner) getMediaTypeForDeleted(path string) (types.PlexMediaType, error) {
 	// Normalize path for comparison
 	normalizedPath := filepath.Clean(path)
 	pathParts := strings.Split(normalizedPath, string(filepath.Separator))
@@ -105,11 +116,11 @@ func (s *Scanner) getMediaTypeForDeleted(path string) (MediaType, error) {
 					// This looks like a TV show structure
 					// Try to verify with section detection
 					section, err := s.findSection(path)
-					if err == nil && section.SectionType == MediaTypeShow {
-						return MediaTypeShow, nil
+					if err == nil && section.SectionType == types.MediaTypeShow {
+						return types.MediaTypeShow, nil
 					}
 					// Even if section detection fails, trust the heuristic
-					return MediaTypeShow, nil
+					return types.MediaTypeShow, nil
 				}
 			}
 		}
@@ -124,11 +135,11 @@ func (s *Scanner) getMediaTypeForDeleted(path string) (MediaType, error) {
 		// Additional heuristic: check for any "season" mention in path
 		pathLower := strings.ToLower(normalizedPath)
 		if strings.Contains(pathLower, "season") {
-			return MediaTypeShow, nil
+			return types.MediaTypeShow, nil
 		}
 
 		// Default to movie if no clear indicators
-		return MediaTypeMovie, nil
+		return types.MediaTypeMovie, nil
 	}
 
 	return section.SectionType, nil
@@ -162,11 +173,11 @@ func (s *Scanner) ScanPath(ctx context.Context, path string, cooldown time.Durat
 // GetScanPath returns the optimal path to scan based on media type.
 // For TV shows, it strips "Season X" folders to scan at the show level.
 // For movies, it returns the parent directory.
-func (s *Scanner) GetScanPath(path string, mediaType MediaType) string {
+func (s *Scanner) GetScanPath(path string, mediaType types.PlexMediaType) string {
 	cleanPath := filepath.Clean(path)
 
 	// For shows, we want to scan at the show level (not season level)
-	if mediaType == MediaTypeShow {
+	if mediaType == types.MediaTypeShow {
 		return s.getShowRootPath(cleanPath)
 	}
 
@@ -197,13 +208,23 @@ func (s *Scanner) getShowRootPath(path string) string {
 
 // findSection locates the Plex library section that contains the given path.
 // It uses longest-prefix matching to handle nested library structures correctly.
-func (s *Scanner) findSection(path string) (*SectionRoot, error) {
+func (s *Scanner) findSection(path string) (*types.PlexSection, error) {
 	cleanPath := filepath.Clean(path)
 
-	// Try to match against each root (already sorted longest-first)
+	// Try to match against each root (already sorted longest-first). Prefer
+	// the root exactly as Plex reported it; if that directory doesn't exist
+	// locally (e.g. Plex sees a container bind-mount path with no local
+	// equivalent), fall back to its path-mapped local root.
 	for _, root := range s.roots {
+		rootPath := root.RootPath
+		if _, err := os.Stat(rootPath); err != nil {
+			if localRoot, ok := s.pathMapper.MapReverse(rootPath); ok {
+				rootPath = localRoot
+			}
+		}
+
 		// Check if path is within this root
-		relPath, err := filepath.Rel(root.RootPath, cleanPath)
+		relPath, err := filepath.Rel(rootPath, cleanPath)
 		if err != nil {
 			continue // Not related to this root
 		}
@@ -229,32 +250,38 @@ func (s *Scanner) findSection(path string) (*SectionRoot, error) {
 }
 
 // GetSectionByTitle retrieves a section by its title.
-func (s *Scanner) GetSectionByTitle(title string) (*SectionRoot, bool) {
+func (s *Scanner) GetSectionByTitle(title string) (*types.PlexSection, bool) {
 	section, ok := s.sections[title]
 	return &section, ok
 }
 
 // GetAllSections returns all discovered library sections.
-func (s *Scanner) GetAllSections() []SectionRoot {
-	sections := make([]SectionRoot, 0, len(s.roots))
+func (s *Scanner) GetAllSections() []types.PlexSection {
+	sections := make([]types.PlexSection, 0, len(s.roots))
 	sections = append(sections, s.roots...)
 	return sections
 }
 
-// MapToPlexPath maps a local filesystem path to path existed on remote plex server
+// MapToPlexPath maps a local filesystem path to path existed on remote plex server.
+// It tries explicit path-mapping rules first (s.pathMapper); if none match, it
+// falls back to the longest-suffix matching against known library roots.
 func (s *Scanner) MapToPlexPath(localPath string) (mapped string, matchedRoot string, ok bool) {
+	if ruleMapped, ruleOk := s.pathMapper.Map(localPath); ruleOk {
+		log.Printf("Mapped path '%s' -> '%s' via path-mapping rule", localPath, ruleMapped)
+		return ruleMapped, "", true
+	}
+
 	if len(s.roots) == 0 {
 		return "", "", false
 	}
 
-	roots := make([]string, 0, len(s.roots))
-	for _, r := range s.roots {
-		if r.RootPath != "" {
-			roots = append(roots, r.RootPath)
-		}
+	mapped, matchedRootPtr := mapToPlexPath(localPath, s.roots)
+	if matchedRootPtr == nil {
+		return "", "", false
 	}
 
-	return mapToPlexPath(localPath, roots)
+	log.Printf("Mapped path '%s' -> '%s' via suffix-matched root", localPath, mapped)
+	return mapped, matchedRootPtr.RootPath, true
 }
 
 // isDigit checks if a byte represents an ASCII digit.
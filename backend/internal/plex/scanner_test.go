@@ -0,0 +1,71 @@
+package plex
+
+import (
+	"os"
+	"path/filepath"
+	"plexwatcher/internal/pathmap"
+	"plexwatcher/internal/types"
+	"testing"
+)
+
+// TestFindSectionMapReverseFallback covers the case where Plex reports a
+// section root that only exists inside its own container (no matching
+// directory on the filesystem the watcher walks) - findSection should fall
+// back to the path-mapped local equivalent via pathmap.MapReverse.
+func TestFindSectionMapReverseFallback(t *testing.T) {
+	localRoot := t.TempDir()
+	moviePath := filepath.Join(localRoot, "Inception (2010)", "Inception.mkv")
+	if err := os.MkdirAll(filepath.Dir(moviePath), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	// Plex only knows this root as /movies inside its container - that path
+	// doesn't exist on the filesystem running this test, so findSection
+	// must map it back to localRoot to resolve the local event path.
+	plexRoot := types.PlexSection{
+		SectionKey:   1,
+		SectionTitle: "Movies",
+		SectionType:  types.MediaTypeMovie,
+		RootPath:     "/movies",
+	}
+
+	s := &Scanner{
+		sections: map[string]types.PlexSection{plexRoot.SectionTitle: plexRoot},
+		roots:    []types.PlexSection{plexRoot},
+		pathMapper: pathmap.New([]types.PathMappingRule{
+			{From: localRoot, To: "/movies"},
+		}),
+	}
+
+	section, err := s.findSection(moviePath)
+	if err != nil {
+		t.Fatalf("findSection: %v", err)
+	}
+	if section.SectionTitle != "Movies" {
+		t.Errorf("section.SectionTitle = %q, want %q", section.SectionTitle, "Movies")
+	}
+}
+
+// TestFindSectionNoFallbackNeeded covers the common case where Plex's
+// reported root exists locally as-is, so MapReverse is never consulted.
+func TestFindSectionNoFallbackNeeded(t *testing.T) {
+	localRoot := t.TempDir()
+	moviePath := filepath.Join(localRoot, "Inception (2010)", "Inception.mkv")
+	if err := os.MkdirAll(filepath.Dir(moviePath), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	plexRoot := types.PlexSection{SectionTitle: "Movies", SectionType: types.MediaTypeMovie, RootPath: localRoot}
+	s := &Scanner{
+		sections: map[string]types.PlexSection{plexRoot.SectionTitle: plexRoot},
+		roots:    []types.PlexSection{plexRoot},
+	}
+
+	section, err := s.findSection(moviePath)
+	if err != nil {
+		t.Fatalf("findSection: %v", err)
+	}
+	if section.SectionTitle != "Movies" {
+		t.Errorf("section.SectionTitle = %q, want %q", section.SectionTitle, "Movies")
+	}
+}
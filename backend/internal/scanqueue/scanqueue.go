@@ -0,0 +1,220 @@
+// Package scanqueue implements a persistent, coalescing queue of pending
+// scan requests. Filesystem events feed entries into the queue keyed on
+// (service, target-path); a worker drains entries once they've been quiet
+// for the cooldown window, so bursts of events against the same directory
+// collapse into a single scan. The queue is mirrored to disk so outstanding
+// entries survive a crash or restart.
+package scanqueue
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// maxAttempts and maxBackoffDelay bound how long a failing entry is retried
+// before Backoff gives up on it and drops it from the queue.
+const (
+	maxAttempts     = 6
+	maxBackoffDelay = 15 * time.Minute
+)
+
+// Entry tracks one pending scan target.
+type Entry struct {
+	Service    string    `json:"service"`
+	Target     string    `json:"target"`
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+	EventCount int       `json:"event_count"`
+
+	// Attempts and NextAttempt implement exponential backoff for entries
+	// whose scan has failed. NextAttempt is zero until the first failure.
+	Attempts    int       `json:"attempts,omitempty"`
+	NextAttempt time.Time `json:"next_attempt,omitempty"`
+}
+
+func key(service, target string) string {
+	return service + "|" + target
+}
+
+// Queue is a coalescing, disk-backed map of pending scan entries. It's safe
+// for concurrent use.
+type Queue struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]*Entry
+}
+
+// Open loads (or creates) the queue state file at stateDir/scanqueue.json.
+// Outstanding entries from a previous run are restored so they can be
+// replayed by a worker.
+func Open(stateDir string) (*Queue, error) {
+	if stateDir == "" {
+		return nil, fmt.Errorf("scanqueue: stateDir must not be empty")
+	}
+	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+		return nil, fmt.Errorf("scanqueue: failed to create state dir: %w", err)
+	}
+
+	q := &Queue{
+		path:    filepath.Join(stateDir, "scanqueue.json"),
+		entries: make(map[string]*Entry),
+	}
+
+	data, err := os.ReadFile(q.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return q, nil
+		}
+		return nil, fmt.Errorf("scanqueue: failed to read state file: %w", err)
+	}
+	if len(data) == 0 {
+		return q, nil
+	}
+	var entries []*Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("scanqueue: failed to parse state file: %w", err)
+	}
+	for _, e := range entries {
+		q.entries[key(e.Service, e.Target)] = e
+	}
+	return q, nil
+}
+
+// Touch records an observed event for (service, target), creating the entry
+// if it doesn't already exist and resetting any backoff.
+func (q *Queue) Touch(service, target string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	k := key(service, target)
+	e, ok := q.entries[k]
+	if !ok {
+		e = &Entry{Service: service, Target: target, FirstSeen: now}
+		q.entries[k] = e
+	}
+	e.LastSeen = now
+	e.EventCount++
+	e.Attempts = 0
+	e.NextAttempt = time.Time{}
+	return q.saveLocked()
+}
+
+// Pending returns the entry for (service, target), if one is queued.
+func (q *Queue) Pending(service, target string) (*Entry, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	e, ok := q.entries[key(service, target)]
+	return e, ok
+}
+
+// Ready returns a copy of every entry that has been quiet for at least
+// cooldown and whose backoff (if any) has elapsed.
+func (q *Queue) Ready(cooldown time.Duration) []Entry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	var ready []Entry
+	for _, e := range q.entries {
+		if now.Sub(e.LastSeen) < cooldown {
+			continue
+		}
+		if !e.NextAttempt.IsZero() && now.Before(e.NextAttempt) {
+			continue
+		}
+		ready = append(ready, *e)
+	}
+	return ready
+}
+
+// Remove drops the entry for (service, target), persisting the change.
+// It's called once a scan succeeds (2xx from the media server).
+func (q *Queue) Remove(service, target string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.entries, key(service, target))
+	return q.saveLocked()
+}
+
+// Backoff records a failed scan attempt for (service, target) and schedules
+// the next retry with exponential backoff plus jitter (capped at
+// maxBackoffDelay), leaving the entry queued. Once an entry has failed
+// maxAttempts times it's dropped instead of rescheduled; the returned bool
+// reports whether that happened.
+func (q *Queue) Backoff(service, target string, base time.Duration) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	k := key(service, target)
+	e, ok := q.entries[k]
+	if !ok {
+		return false, nil // already removed/resolved elsewhere
+	}
+	e.Attempts++
+	if e.Attempts >= maxAttempts {
+		delete(q.entries, k)
+		return true, q.saveLocked()
+	}
+
+	delay := base << uint(e.Attempts-1) // 1x, 2x, 4x, 8x, ...
+	if delay > maxBackoffDelay || delay <= 0 {
+		delay = maxBackoffDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay/4) + 1))
+	e.NextAttempt = time.Now().Add(delay + jitter)
+	return false, q.saveLocked()
+}
+
+// Flush returns every entry currently queued for service, bypassing the
+// cooldown and backoff checks Ready applies. Used to force an immediate
+// drain on demand (see POST /queue/flush).
+func (q *Queue) Flush(service string) []Entry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var out []Entry
+	for _, e := range q.entries {
+		if e.Service == service {
+			out = append(out, *e)
+		}
+	}
+	return out
+}
+
+// DepthByService returns the number of queued entries per service.
+func (q *Queue) DepthByService() map[string]int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	depth := make(map[string]int)
+	for _, e := range q.entries {
+		depth[e.Service]++
+	}
+	return depth
+}
+
+// saveLocked writes the full entry set to disk. Callers must hold q.mu.
+func (q *Queue) saveLocked() error {
+	entries := make([]*Entry, 0, len(q.entries))
+	for _, e := range q.entries {
+		entries = append(entries, e)
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("scanqueue: failed to marshal state: %w", err)
+	}
+
+	// write-then-rename so a crash mid-write can't corrupt the state file
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("scanqueue: failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp, q.path); err != nil {
+		return fmt.Errorf("scanqueue: failed to replace state file: %w", err)
+	}
+	return nil
+}
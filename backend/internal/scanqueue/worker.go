@@ -0,0 +1,84 @@
+package scanqueue
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ScanFunc performs the actual scan for one queued entry. A nil error is
+// treated as success (2xx); any error triggers backoff and leaves the entry
+// queued for retry.
+type ScanFunc func(ctx context.Context, target string) error
+
+// Worker periodically drains Ready entries for one service, invoking Scan
+// for each and removing it on success or backing it off on failure.
+type Worker struct {
+	Queue       *Queue
+	Service     string
+	Cooldown    time.Duration // how long an entry must be quiet before it's eligible
+	PollEvery   time.Duration // how often to check for ready entries
+	BaseBackoff time.Duration // starting backoff delay after a failed scan
+	Scan        ScanFunc
+}
+
+// Run drains the queue until ctx is cancelled. It's meant to be started in
+// its own goroutine, once per configured service.
+func (wk *Worker) Run(ctx context.Context) {
+	poll := wk.PollEvery
+	if poll <= 0 {
+		poll = time.Second
+	}
+	backoff := wk.BaseBackoff
+	if backoff <= 0 {
+		backoff = 5 * time.Second
+	}
+
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	// Replay outstanding entries immediately so anything left over from a
+	// crash or restart doesn't wait a full poll interval.
+	wk.drain(ctx, backoff)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			wk.drain(ctx, backoff)
+		}
+	}
+}
+
+func (wk *Worker) drain(ctx context.Context, backoff time.Duration) {
+	wk.runEntries(ctx, wk.Queue.Ready(wk.Cooldown), backoff)
+}
+
+// Flush runs Scan immediately against every queued entry for this service,
+// bypassing the cooldown and backoff gates drain normally waits on. Used by
+// POST /queue/flush to force a drain on demand.
+func (wk *Worker) Flush(ctx context.Context) {
+	backoff := wk.BaseBackoff
+	if backoff <= 0 {
+		backoff = 5 * time.Second
+	}
+	wk.runEntries(ctx, wk.Queue.Flush(wk.Service), backoff)
+}
+
+func (wk *Worker) runEntries(ctx context.Context, entries []Entry, backoff time.Duration) {
+	for _, entry := range entries {
+		if entry.Service != wk.Service {
+			continue
+		}
+		if err := wk.Scan(ctx, entry.Target); err != nil {
+			gaveUp, _ := wk.Queue.Backoff(entry.Service, entry.Target, backoff)
+			if gaveUp {
+				slog.Error("scan permanently failed, dropping from queue",
+					"service", entry.Service, "target", entry.Target, "error", err)
+			}
+			continue
+		}
+		_ = wk.Queue.Remove(entry.Service, entry.Target)
+	}
+}
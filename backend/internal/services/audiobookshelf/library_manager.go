@@ -3,73 +3,73 @@ package audiobookshelf
 import (
 	"context"
 	"fmt"
-	"path/filepath"
+	"plexwatcher/internal/pathmap"
+	"plexwatcher/internal/pathmatch"
 	"plexwatcher/internal/types"
-	"strings"
+	"plexwatcher/pkg/logger"
 )
 
 type LibraryManager struct {
 	Client    *AbsClient
 	Libraries []types.AbsLibrary
+
+	// pathMapper applies explicit From->To rewrite rules before falling
+	// back to longest-prefix library matching. Holds no rules (and so never
+	// matches) if none were configured.
+	pathMapper *pathmap.PathMapper
 }
 
-func NewLibraryManager(ctx context.Context, client *AbsClient) (*LibraryManager, error) {
+// NewLibraryManager creates a new LibraryManager, fetching libraries from
+// client. pathMappings may be nil; when empty, path resolution falls back
+// entirely to longest-prefix library matching.
+func NewLibraryManager(ctx context.Context, client *AbsClient, pathMappings []types.PathMappingRule) (*LibraryManager, error) {
 	libs, _, err := client.ListLibraries(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list libraries: %w", err)
 	}
 	return &LibraryManager{
-		Client:    client,
-		Libraries: libs,
+		Client:     client,
+		Libraries:  libs,
+		pathMapper: pathmap.New(pathMappings),
 	}, nil
 }
 
+// GetLibraryByPath finds the library whose folder contains path, using
+// longest-prefix matching (shared with
+// jellyfin.LibraryManager.MapToJellyfinPath via pathmatch.LongestPrefix).
 func (lm *LibraryManager) GetLibraryByPath(path string) (*types.AbsLibrary, error) {
 	if len(lm.Libraries) == 1 {
 		return &lm.Libraries[0], nil // only one library, return it
 	}
 
-	// normalize path for comparison
-	normalized := filepath.ToSlash(filepath.Clean(path))
-	lower := strings.ToLower(normalized)
-
-	var longestMatch string
-	var matchedLibrary *types.AbsLibrary
-
+	var roots []string
+	var owners []*types.AbsLibrary
 	for i := range lm.Libraries {
 		lib := &lm.Libraries[i]
 		for _, libPath := range lib.Folders {
-			// normalize library path
-			libNormalized := filepath.ToSlash(filepath.Clean(libPath.FullPath))
-			libLower := strings.ToLower(libNormalized)
-
-			if strings.HasPrefix(lower, libLower) {
-				// This check ensures we match a full directory name, not just a partial one.
-				// e.g., it prevents "/media/audiobooks-new" from matching "/media/audiobooks"
-				// The path must either be an exact match or be followed by a path separator.
-				isExactMatch := len(libLower) == len(lower)
-				isSubPath := len(lower) > len(libLower) && lower[len(libLower)] == '/'
-				if isExactMatch || isSubPath {
-					// If this match is more specific (longer) than the previous best, update it.
-					if len(libLower) > len(longestMatch) {
-						longestMatch = libLower
-						matchedLibrary = lib
-					}
-				}
-			}
+			roots = append(roots, libPath.FullPath)
+			owners = append(owners, lib)
 		}
 	}
-	if matchedLibrary != nil {
-		return matchedLibrary, nil
+
+	idx, ok := pathmatch.LongestPrefix(path, roots)
+	if !ok {
+		return nil, fmt.Errorf("no library found containing path: %s", path)
 	}
-	return nil, fmt.Errorf("no library found containing path: %s", path)
+	return owners[idx], nil
 }
 
 func (lm *LibraryManager) ScanPath(ctx context.Context, path string) error {
+	if mapped, ok := lm.pathMapper.Map(path); ok {
+		logger.Trace(logger.SubsystemAbs, "mapped path via path-mapping rule", "path", path, "mapped", mapped)
+		path = mapped
+	}
+
 	lib, err := lm.GetLibraryByPath(path)
 	if err != nil {
 		return fmt.Errorf("scan path: %w", err)
 	}
+	logger.Trace(logger.SubsystemAbs, "resolved library for scan path", "path", path, "library_id", lib.Id, "library", lib.Name)
 	code, err := lm.Client.ScanLibrary(ctx, lib.Id)
 	if err != nil {
 		return fmt.Errorf("scan library: (%d) %w", code, err)
@@ -0,0 +1,183 @@
+package jellyfin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"plexwatcher/internal/http/request"
+	"plexwatcher/internal/types"
+	"time"
+)
+
+// JellyfinClient is a client for interacting with the Jellyfin/Emby server API.
+// Emby shares the same `X-Emby-Token` auth scheme and refresh endpoints, so one
+// client covers both.
+type JellyfinClient struct {
+	BaseURL *url.URL
+	ApiKey  string
+	HTTP    *http.Client
+}
+
+// NewClient creates a new JellyfinClient with the given base URL and API key.
+func NewClient(baseURL string, apiKey string) (*JellyfinClient, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("base URL is empty")
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL, missing scheme or host: %s", baseURL)
+	}
+
+	return &JellyfinClient{
+		BaseURL: u,
+		ApiKey:  apiKey,
+		HTTP: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}, nil
+}
+
+// ListLibraries returns the server's virtual folders, each carrying the
+// filesystem locations backing it.
+func (c *JellyfinClient) ListLibraries(ctx context.Context) ([]types.JellyfinLibrary, error) {
+	u := c.buildURL([]string{"Library", "VirtualFolders"}, nil)
+	req, err := c.requestWithAuth(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 4<<10)) // <-- only return 4096 bytes of message
+		return nil, fmt.Errorf("jellyfin list libraries: http %d: %s", res.StatusCode, string(b))
+	}
+
+	var libs types.JellyfinLibraryResponse
+	if err := json.NewDecoder(res.Body).Decode(&libs); err != nil {
+		return nil, fmt.Errorf("jellyfin list libraries: decode: %w", err)
+	}
+	return libs, nil
+}
+
+// ScanLibrary triggers a full library scan. Use this when a specific item
+// can't be resolved from a path (e.g. a brand new top-level folder).
+func (c *JellyfinClient) ScanLibrary(ctx context.Context) error {
+	u := c.buildURL([]string{"Library", "Refresh"}, nil)
+	req, err := c.requestWithAuth(ctx, http.MethodPost, u, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 4<<10))
+		return fmt.Errorf("jellyfin refresh library: http %d: %s", res.StatusCode, string(b))
+	}
+	return nil
+}
+
+// mediaUpdateInfo is one entry in a /Library/Media/Updated request body.
+type mediaUpdateInfo struct {
+	Path       string `json:"Path"`
+	UpdateType string `json:"UpdateType"`
+}
+
+// RefreshPath notifies Jellyfin that the media at path changed, via
+// /Library/Media/Updated - the path-scoped equivalent of Plex's
+// ScanSectionPath. Jellyfin resolves which library/item covers the path
+// server-side, so no item id lookup is needed beforehand.
+func (c *JellyfinClient) RefreshPath(ctx context.Context, path string) error {
+	body := struct {
+		Updates []mediaUpdateInfo `json:"Updates"`
+	}{Updates: []mediaUpdateInfo{{Path: path, UpdateType: "Modified"}}}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("jellyfin refresh path: marshal body: %w", err)
+	}
+
+	u := c.buildURL([]string{"Library", "Media", "Updated"}, nil)
+	req, err := c.requestWithAuth(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 4<<10))
+		return fmt.Errorf("jellyfin refresh path: http %d: %s", res.StatusCode, string(msg))
+	}
+	return nil
+}
+
+// RefreshItemByPath refreshes a single item, identified by its Jellyfin item
+// id, in place. Callers resolve the id from a path via the LibraryManager.
+func (c *JellyfinClient) RefreshItemByPath(ctx context.Context, itemId string) error {
+	u := c.buildURL([]string{"Items", itemId, "Refresh"}, url.Values{
+		"Recursive":           {"true"},
+		"MetadataRefreshMode": {"Default"},
+	})
+	req, err := c.requestWithAuth(ctx, http.MethodPost, u, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 4<<10))
+		return fmt.Errorf("jellyfin refresh item %s: http %d: %s", itemId, res.StatusCode, string(b))
+	}
+	return nil
+}
+
+// ======================
+// UTILS
+// ======================
+
+func (c *JellyfinClient) requestWithAuth(ctx context.Context, method string, u *url.URL, body io.Reader) (*http.Request, error) {
+	r, err := request.NewRequest(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("X-Emby-Token", c.ApiKey)
+	return r, nil
+}
+
+func (c *JellyfinClient) buildURL(parts []string, q url.Values) *url.URL {
+	u := *c.BaseURL // copy
+	u.Path, _ = url.JoinPath(c.BaseURL.Path, parts...)
+	if q == nil {
+		q = url.Values{}
+	}
+	u.RawQuery = q.Encode()
+	return &u
+}
@@ -0,0 +1,74 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+	"plexwatcher/internal/pathmatch"
+	"plexwatcher/internal/types"
+	"plexwatcher/pkg/logger"
+)
+
+// LibraryManager maps filesystem paths to Jellyfin virtual folders and
+// triggers scans against the Jellyfin/Emby API.
+type LibraryManager struct {
+	Client    *JellyfinClient
+	Libraries []types.JellyfinLibrary
+}
+
+// NewLibraryManager fetches and caches the server's virtual folders.
+func NewLibraryManager(ctx context.Context, client *JellyfinClient) (*LibraryManager, error) {
+	libs, err := client.ListLibraries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list libraries: %w", err)
+	}
+	return &LibraryManager{
+		Client:    client,
+		Libraries: libs,
+	}, nil
+}
+
+// ListLibraries returns the cached virtual folders.
+func (lm *LibraryManager) ListLibraries() []types.JellyfinLibrary {
+	return lm.Libraries
+}
+
+// MapToJellyfinPath finds the virtual folder whose location contains the
+// given local path, using longest-prefix matching across all configured
+// locations (shared with
+// audiobookshelf.LibraryManager.GetLibraryByPath via pathmatch.LongestPrefix).
+func (lm *LibraryManager) MapToJellyfinPath(path string) (*types.JellyfinLibrary, error) {
+	var roots []string
+	var owners []*types.JellyfinLibrary
+	for i := range lm.Libraries {
+		lib := &lm.Libraries[i]
+		for _, loc := range lib.Locations {
+			roots = append(roots, loc)
+			owners = append(owners, lib)
+		}
+	}
+
+	idx, ok := pathmatch.LongestPrefix(path, roots)
+	if !ok {
+		return nil, fmt.Errorf("no jellyfin library found containing path: %s", path)
+	}
+	return owners[idx], nil
+}
+
+// ScanPath refreshes the media at path via /Library/Media/Updated, the
+// path-scoped equivalent of Plex's ScanSectionPath. If that fails, it falls
+// back to a full library refresh so the change is still picked up.
+func (lm *LibraryManager) ScanPath(ctx context.Context, path string) error {
+	lib, err := lm.MapToJellyfinPath(path)
+	if err != nil {
+		return fmt.Errorf("scan path: %w", err)
+	}
+	logger.Trace(logger.SubsystemJellyfin, "resolved library for scan path", "path", path, "library_id", lib.Id, "library", lib.Name)
+
+	if err := lm.Client.RefreshPath(ctx, path); err != nil {
+		logger.For(logger.SubsystemJellyfin).Warn("path-scoped refresh failed, falling back to full library refresh", "path", path, "error", err)
+		if fallbackErr := lm.Client.ScanLibrary(ctx); fallbackErr != nil {
+			return fmt.Errorf("scan library (after path refresh failed: %v): %w", err, fallbackErr)
+		}
+	}
+	return nil
+}
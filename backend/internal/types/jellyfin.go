@@ -0,0 +1,10 @@
+package types
+
+// JellyfinLibraryResponse is the response shape of GET /Library/VirtualFolders.
+type JellyfinLibraryResponse []JellyfinLibrary
+
+type JellyfinLibrary struct {
+	Id        string   `json:"ItemId"`
+	Name      string   `json:"Name"`
+	Locations []string `json:"Locations"`
+}
@@ -0,0 +1,11 @@
+package types
+
+// PathMappingRule rewrites a local filesystem path prefix to the path the
+// remote media server sees, for deployments where the watcher and the
+// server don't share a filesystem view (e.g. watcher on host, Plex in
+// Docker with a different bind-mount target).
+type PathMappingRule struct {
+	From            string `json:"from"`
+	To              string `json:"to"`
+	CaseInsensitive bool   `json:"case_insensitive,omitempty"`
+}
@@ -0,0 +1,24 @@
+package types
+
+import "time"
+
+// RootProgress tracks recursive watch setup for a single top-level watch
+// root: how many of its subdirectories fsnotify knows about so far, and
+// when the scan started/finished.
+type RootProgress struct {
+	TotalDirs   int       `json:"total_dirs"`
+	AddedDirs   int       `json:"added_dirs"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at,omitempty"`
+}
+
+// RecursiveProgress summarizes background recursive watch setup across every
+// enabled root, so a caller can tell "running=true" (the event loop is up)
+// apart from "every subdirectory actually has a watch yet".
+type RecursiveProgress struct {
+	TotalDirs   int                     `json:"total_dirs"`
+	AddedDirs   int                     `json:"added_dirs"`
+	StartedAt   time.Time               `json:"started_at"`
+	CompletedAt time.Time               `json:"completed_at,omitempty"`
+	PerRoot     map[string]RootProgress `json:"per_root"`
+}
@@ -4,6 +4,31 @@ type RequestStart struct {
 	ServiceConfigs map[ServiceType]ServiceConfig `json:"service_configs"` // credentials per service
 	WatchedDirs    []WatchDir                    `json:"watched_dirs"`
 	Cooldown       int                           `json:"cooldown"` // seconds; used as debounce
+
+	// QuietPeriodSec, StabilityPollIntervalSec and MaxSettleWaitSec
+	// configure the settle gate (see fs_watcher.Config) that protects
+	// against scanning a directory while a download client is still
+	// moving files into it. All are seconds; QuietPeriodSec <= 0 disables
+	// the gate entirely.
+	QuietPeriodSec           int `json:"quiet_period_sec,omitempty"`
+	StabilityPollIntervalSec int `json:"stability_poll_interval_sec,omitempty"`
+	MaxSettleWaitSec         int `json:"max_settle_wait_sec,omitempty"`
+
+	// StableChecks is how many consecutive stability polls a path must
+	// pass (unchanged size and mtime) before the settle gate dispatches
+	// it. See fs_watcher.Config.StableChecks. Defaults to 1 if unset.
+	StableChecks int `json:"stable_checks,omitempty"`
+
+	// BreakerMaxEventsPerWindow, BreakerWindowSec and BreakerCooldownSec
+	// configure the per-service circuit breaker that stops forwarding
+	// events to a media server's scanner once a watch root is firing an
+	// abnormal number of events (e.g. someone points the watcher at "/").
+	// BreakerMaxEventsPerWindow <= 0 disables the breaker entirely, matching
+	// QuietPeriodSec's "0 disables" convention. Unset window/cooldown fall
+	// back to 60s and 5 minutes respectively.
+	BreakerMaxEventsPerWindow int `json:"breaker_max_events_per_window,omitempty"`
+	BreakerWindowSec          int `json:"breaker_window_sec,omitempty"`
+	BreakerCooldownSec        int `json:"breaker_cooldown_sec,omitempty"`
 }
 
 type RequestScan struct {
@@ -14,12 +39,20 @@ type RequestScan struct {
 type ServiceConfig struct {
 	ServerUrl string `json:"server_url"`
 	Token     string `json:"token"`
+
+	// PathMappings rewrites local paths to the paths the remote server sees,
+	// tried longest-From-first before falling back to section/library root
+	// matching. Populated from the request body only: the server does not
+	// read the PATH_MAP env var into it (pathmap.ParseEnv parses that format
+	// but nothing calls it). Used by the Plex scanner and the Audiobookshelf
+	// library manager; the Jellyfin library manager ignores it.
+	PathMappings []PathMappingRule `json:"path_mappings,omitempty"`
 }
 
 type WatchDir struct {
-	Path    string      `json:"path"`    // absolute path to watch
-	Service ServiceType `json:"service"` // which service this dir is for (plex, audiobookshelf, etc)
-	Enabled bool        `json:"enabled"` // whether this dir is enabled for watching
+	Path     string        `json:"path"`     // absolute path to watch
+	Services []ServiceType `json:"services"` // which services to dispatch this dir's events to (plex, audiobookshelf, etc); a single event fans out to all of them concurrently
+	Enabled  bool          `json:"enabled"`  // whether this dir is enabled for watching
 }
 
 type ServiceType string
@@ -27,6 +60,7 @@ type ServiceType string
 const (
 	ServicePlex           ServiceType = "plex"
 	ServiceAudiobookshelf ServiceType = "audiobookshelf"
+	ServiceJellyfin       ServiceType = "jellyfin"
 )
 
 /*
@@ -42,8 +76,8 @@ const (
     }
   },
   "watched_dirs": [
-    {"path": "/media/tv-shows", "service": "plex", "enabled": true},
-    {"path": "/media/audiobooks", "service": "audiobookshelf", "enabled": true}
+    {"path": "/media/tv-shows", "services": ["plex", "jellyfin"], "enabled": true},
+    {"path": "/media/audiobooks", "services": ["audiobookshelf"], "enabled": true}
   ],
   "cooldown": 5
 }
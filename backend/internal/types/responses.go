@@ -21,4 +21,15 @@ type StatusResponse struct {
 	WatchDirs  []WatchDir `json:"watch_dirs"`
 	Server     *string    `json:"server,omitempty"`
 	Cooldown   int        `json:"cooldown"`
+
+	// Breakers reports the circuit breaker's state per service (e.g.
+	// {"plex": "open"}) so the frontend can surface something like "Plex
+	// scans paused: too many events". Omitted entirely if the breaker
+	// hasn't tracked any dispatches yet.
+	Breakers map[ServiceType]string `json:"breakers,omitempty"`
+
+	// Progress reports background recursive watch setup, so a client can
+	// tell "the event loop is running" apart from "every subdirectory
+	// actually has a watch yet".
+	Progress RecursiveProgress `json:"progress"`
 }
@@ -0,0 +1,193 @@
+package watcher_manager
+
+import (
+	"log/slog"
+	"plexwatcher/internal/types"
+	"slices"
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// halfOpenProbeWindow is how long a half-open breaker waits after letting
+// its probe dispatch through before declaring the probe successful.
+const halfOpenProbeWindow = 10 * time.Second
+
+// circuitBreaker trips per-service when dispatches exceed maxEventsPerWindow
+// within window, so a misconfigured watch root (e.g. pointed at "/") can't
+// hammer a media server's API into a rate limit or ban.
+//
+// Recovery: an open breaker drops every dispatch until cooldown has passed.
+// The next dispatch after that goes through as a probe and moves the
+// breaker to half-open. While half-open, further dispatches are dropped but
+// still counted; exceeding maxEventsPerWindow re-opens the breaker, and the
+// first dispatch once halfOpenProbeWindow has passed without that closes
+// it (and runs onRecover).
+type circuitBreaker struct {
+	window             time.Duration
+	maxEventsPerWindow int
+	cooldown           time.Duration
+
+	// onRecover, if set, is run in its own goroutine with a service's
+	// top-level watch roots each time that service's breaker closes again
+	// after a trip, so the caller can fold whatever it dropped while open
+	// into one deferred rescan per root.
+	onRecover func(service types.ServiceType, watchDirs []types.WatchDir)
+
+	mu        sync.Mutex
+	byService map[types.ServiceType]*breakerEntry
+}
+
+type breakerEntry struct {
+	state       breakerState
+	windowStart time.Time
+	count       int
+	openedAt    time.Time
+	halfOpenAt  time.Time
+}
+
+func newCircuitBreaker(window time.Duration, maxEventsPerWindow int, cooldown time.Duration, onRecover func(types.ServiceType, []types.WatchDir)) *circuitBreaker {
+	return &circuitBreaker{
+		window:             window,
+		maxEventsPerWindow: maxEventsPerWindow,
+		cooldown:           cooldown,
+		onRecover:          onRecover,
+		byService:          make(map[types.ServiceType]*breakerEntry),
+	}
+}
+
+// watchDirsForService filters watchDirs down to the ones owned by service,
+// for trip/recovery logging and for the onRecover callback.
+func watchDirsForService(watchDirs []types.WatchDir, service types.ServiceType) []types.WatchDir {
+	var owned []types.WatchDir
+	for _, d := range watchDirs {
+		if slices.Contains(d.Services, service) {
+			owned = append(owned, d)
+		}
+	}
+	return owned
+}
+
+// allow reports whether a dispatch for service should proceed. It records
+// the dispatch against the rolling window, trips the breaker once the
+// window's count exceeds maxEventsPerWindow, and implements the
+// open -> half-open -> closed recovery cycle described on circuitBreaker.
+// watchDirs is used only for logging the offending roots and, on recovery,
+// for the onRecover callback.
+func (b *circuitBreaker) allow(service types.ServiceType, watchDirs []types.WatchDir) bool {
+	if b.maxEventsPerWindow <= 0 {
+		return true // breaker disabled
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	e, ok := b.byService[service]
+	if !ok {
+		e = &breakerEntry{windowStart: now}
+		b.byService[service] = e
+	}
+
+	switch e.state {
+	case breakerOpen:
+		if now.Sub(e.openedAt) < b.cooldown {
+			return false
+		}
+		// cooldown elapsed: let exactly one probe dispatch through, starting
+		// the rolling-window accounting fresh so the probe isn't re-tripped
+		// by the stale over-threshold count that caused the original trip
+		e.state = breakerHalfOpen
+		e.halfOpenAt = now
+		e.windowStart = now
+		e.count = 0
+		slog.Warn("circuit breaker half-open, allowing probe dispatch", "service", service)
+		return true
+
+	case breakerHalfOpen:
+		if now.Sub(e.windowStart) >= b.window {
+			e.windowStart = now
+			e.count = 0
+		}
+		e.count++
+		if e.count > b.maxEventsPerWindow {
+			// still misbehaving during the probe: re-trip
+			e.state = breakerOpen
+			e.openedAt = now
+			slog.Warn("circuit breaker re-tripped during half-open probe", "service", service, "count", e.count, "window", b.window)
+			return false
+		}
+		if now.Sub(e.halfOpenAt) < halfOpenProbeWindow {
+			// the probe already went through on the open -> half-open
+			// transition: drop the rest until the probe window has passed
+			return false
+		}
+		// probe window elapsed without exceeding the threshold: fully close
+		e.state = breakerClosed
+		e.windowStart = now
+		e.count = 0
+		slog.Info("circuit breaker closed after successful probe", "service", service)
+		if b.onRecover != nil {
+			owned := watchDirsForService(watchDirs, service)
+			go b.onRecover(service, owned)
+		}
+		return true
+
+	case breakerClosed:
+		if now.Sub(e.windowStart) >= b.window {
+			e.windowStart = now
+			e.count = 0
+		}
+		e.count++
+		if e.count > b.maxEventsPerWindow {
+			e.state = breakerOpen
+			e.openedAt = now
+			owned := watchDirsForService(watchDirs, service)
+			paths := make([]string, len(owned))
+			for i, d := range owned {
+				paths[i] = d.Path
+			}
+			slog.Warn("circuit breaker tripped: too many events",
+				"service", service, "count", e.count, "window", b.window, "watch_dirs", paths)
+			return false
+		}
+	}
+	return true
+}
+
+// snapshot returns the current state of every service the breaker has seen
+// a dispatch for, e.g. {"plex": "closed"}.
+func (b *circuitBreaker) snapshot() map[types.ServiceType]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make(map[types.ServiceType]string, len(b.byService))
+	for svc, e := range b.byService {
+		out[svc] = e.state.String()
+	}
+	return out
+}
+
+// resetAll clears every service's breaker state back to closed.
+func (b *circuitBreaker) resetAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.byService = make(map[types.ServiceType]*breakerEntry)
+}
@@ -1,6 +1,7 @@
 package watcher_manager
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"plexwatcher/internal/fs_watcher"
@@ -12,6 +13,7 @@ import (
 // handlerRegistry maps services to their event handlers
 type handlerRegistry struct {
 	handler map[types.ServiceType]fs_watcher.Handler
+	breaker *circuitBreaker // nil until SetBreaker is called; Dispatch treats nil as always-allow
 	mu      sync.Mutex
 }
 
@@ -34,27 +36,94 @@ func (r *handlerRegistry) Get(service types.ServiceType) (fs_watcher.Handler, bo
 	return h, ok
 }
 
+// SetBreaker installs (or replaces) the circuit breaker guarding Dispatch.
+func (r *handlerRegistry) SetBreaker(b *circuitBreaker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.breaker = b
+}
+
+// Dispatch fans the event out to every service the matched watch dir
+// names, concurrently, so e.g. a single fs event under a dir shared by
+// Plex and Jellyfin triggers both scans without one waiting on the other.
+// Each service's handler call is independent: one failing (missing
+// handler, tripped breaker) doesn't stop the others from running. Errors
+// from individual services are joined into a single returned error.
 func (r *handlerRegistry) Dispatch(event fs_watcher.Event, watchDirs []types.WatchDir) error {
-	service := findServiceForPath(event.Path, watchDirs)
-	if service == "" {
+	services := findServicesForPath(event.Path, watchDirs)
+	if len(services) == 0 {
 		return fmt.Errorf("no service found for path: %s", event.Path)
 	}
+	if len(services) == 1 {
+		return r.dispatchOne(services[0], event, watchDirs)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(services))
+	for i, service := range services {
+		wg.Add(1)
+		go func(i int, service types.ServiceType) {
+			defer wg.Done()
+			errs[i] = r.dispatchOne(service, event, watchDirs)
+		}(i, service)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// dispatchOne runs a single service's handler for event, subject to that
+// service's circuit breaker.
+func (r *handlerRegistry) dispatchOne(service types.ServiceType, event fs_watcher.Event, watchDirs []types.WatchDir) error {
 	handler, ok := r.Get(service)
 	if !ok {
 		return fmt.Errorf("no handler registered for service %s (path: %s)", service, event.Path)
 	}
 
+	r.mu.Lock()
+	breaker := r.breaker
+	r.mu.Unlock()
+	if breaker != nil && !breaker.allow(service, watchDirs) {
+		return fmt.Errorf("circuit breaker open for service %s, dropping event for path: %s", service, event.Path)
+	}
+
 	handler(event)
 	return nil
 }
 
-// findServiceForPath finds which service owns this path (longest prefix match)
-func findServiceForPath(eventPath string, watchDirs []types.WatchDir) types.ServiceType {
+// BreakerStatus returns the current state of every service the breaker has
+// tracked, or nil if no breaker has been configured.
+func (r *handlerRegistry) BreakerStatus() map[types.ServiceType]string {
+	r.mu.Lock()
+	breaker := r.breaker
+	r.mu.Unlock()
+	if breaker == nil {
+		return nil
+	}
+	return breaker.snapshot()
+}
+
+// ResetBreaker clears every service's breaker state back to closed. It is a
+// no-op if no breaker has been configured.
+func (r *handlerRegistry) ResetBreaker() {
+	r.mu.Lock()
+	breaker := r.breaker
+	r.mu.Unlock()
+	if breaker != nil {
+		breaker.resetAll()
+	}
+}
+
+// findServicesForPath finds which services own this path, using longest
+// prefix match against the configured watch dirs. A watch dir can name
+// multiple services, in which case all of them are returned so the caller
+// can fan the event out to each.
+func findServicesForPath(eventPath string, watchDirs []types.WatchDir) []types.ServiceType {
 	normalized := filepath.Clean(eventPath)
 	lower := strings.ToLower(normalized)
 
 	var longestMatch string
-	var matchedService types.ServiceType
+	var matchedServices []types.ServiceType
 
 	for _, dir := range watchDirs {
 		if !dir.Enabled {
@@ -64,9 +133,9 @@ func findServiceForPath(eventPath string, watchDirs []types.WatchDir) types.Serv
 		if strings.HasPrefix(lower, watchPath) {
 			if len(watchPath) > len(longestMatch) {
 				longestMatch = watchPath
-				matchedService = dir.Service
+				matchedServices = dir.Services
 			}
 		}
 	}
-	return matchedService
+	return matchedServices
 }
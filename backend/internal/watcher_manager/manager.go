@@ -6,17 +6,40 @@ import (
 	"log/slog"
 	"plexwatcher/internal/fs_watcher"
 	"plexwatcher/internal/types"
+	"plexwatcher/pkg/logger"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// defaultBreakerWindow and defaultBreakerCooldown apply when a start request
+// enables the circuit breaker (BreakerMaxEventsPerWindow > 0) without
+// specifying its own window/cooldown.
+const (
+	defaultBreakerWindow   = 60 * time.Second
+	defaultBreakerCooldown = 5 * time.Minute
+)
+
 type Manager struct {
-	mutex       sync.Mutex
-	watcher     *fs_watcher.FsWatcher
-	cancel      context.CancelFunc
-	running     bool
-	registry    *handlerRegistry
-	watchedDirs []types.WatchDir // cached watch dirs for dispatch
+	mutex    sync.Mutex
+	watcher  *fs_watcher.FsWatcher
+	cancel   context.CancelFunc
+	running  bool
+	registry *handlerRegistry
+
+	// watchedDirs is the cached watch dir set the dispatch handler reads on
+	// every fs event. It's an atomic snapshot rather than a plain slice
+	// guarded by mutex because the handler runs from the fs_watcher event
+	// loop goroutine and can fire while Reconfigure is still inside its own
+	// m.mutex-held critical section (or even before Start's has released
+	// it) - swapping a pointer avoids both that race and any lock ordering
+	// concern between the two.
+	watchedDirs atomic.Pointer[[]types.WatchDir]
+
+	// breakerRecoverHook, if set, is invoked (in its own goroutine) with a
+	// service's top-level watch roots each time its circuit breaker closes
+	// again after a trip - see SetBreakerRecoverHook.
+	breakerRecoverHook func(service types.ServiceType, watchDirs []types.WatchDir)
 }
 
 func NewManager() *Manager {
@@ -30,6 +53,18 @@ func (m *Manager) RegisterHandler(service types.ServiceType, handler fs_watcher.
 	m.registry.Register(service, handler)
 }
 
+// SetBreakerRecoverHook installs the callback the circuit breaker runs once
+// a service's breaker closes again after having tripped. It coalesces
+// whatever fine-grained events were dropped while the breaker was open into
+// a single deferred full-directory rescan per watch root - see the API
+// layer's registration of this hook for how that's done in practice. Must
+// be called before Start for the upcoming run to pick it up.
+func (m *Manager) SetBreakerRecoverHook(hook func(service types.ServiceType, watchDirs []types.WatchDir)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.breakerRecoverHook = hook
+}
+
 func (m *Manager) Start(req types.RequestStart) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -46,11 +81,23 @@ func (m *Manager) Start(req types.RequestStart) error {
 	}
 
 	// cache watched dir for dispatcher
-	m.watchedDirs = req.WatchedDirs
+	m.watchedDirs.Store(&req.WatchedDirs)
+
+	breakerWindow := time.Duration(req.BreakerWindowSec) * time.Second
+	if breakerWindow <= 0 {
+		breakerWindow = defaultBreakerWindow
+	}
+	breakerCooldown := time.Duration(req.BreakerCooldownSec) * time.Second
+	if breakerCooldown <= 0 {
+		breakerCooldown = defaultBreakerCooldown
+	}
+	m.registry.SetBreaker(newCircuitBreaker(breakerWindow, req.BreakerMaxEventsPerWindow, breakerCooldown, m.breakerRecoverHook))
 
 	// create single handler that dispatches to service-specific handlers
 	handler := func(event fs_watcher.Event) {
-		err := m.registry.Dispatch(event, m.watchedDirs)
+		logger.Trace(logger.SubsystemDispatch, "dispatching event", "path", event.Path, "op", event.Op.String())
+		watchedDirs := *m.watchedDirs.Load()
+		err := m.registry.Dispatch(event, watchedDirs)
 		if err != nil {
 			// log error but do not stop watcher
 			slog.Warn("failed to dispatch event", "error", err, "event", event)
@@ -58,10 +105,14 @@ func (m *Manager) Start(req types.RequestStart) error {
 	}
 
 	cfg := fs_watcher.Config{
-		Dirs:           req.WatchedDirs,
-		Recursive:      true,
-		DebounceWindow: debounce,
-		Handler:        handler,
+		Dirs:                  req.WatchedDirs,
+		Recursive:             true,
+		DebounceWindow:        debounce,
+		QuietPeriod:           time.Duration(req.QuietPeriodSec) * time.Second,
+		StabilityPollInterval: time.Duration(req.StabilityPollIntervalSec) * time.Second,
+		MaxSettleWait:         time.Duration(req.MaxSettleWaitSec) * time.Second,
+		StableChecks:          req.StableChecks,
+		Handler:               handler,
 	}
 	watcher, err := fs_watcher.NewPlexWatcher(cfg)
 	if err != nil {
@@ -79,6 +130,29 @@ func (m *Manager) Start(req types.RequestStart) error {
 	return nil
 }
 
+// Reconfigure updates the running watcher's directory set in place -
+// added/newly-enabled dirs get fresh watches, dropped/disabled ones are
+// torn down, and unchanged dirs are left alone so their in-flight
+// debounce/settle state survives. The watcher must already be running; use
+// Start for the initial run.
+func (m *Manager) Reconfigure(req types.RequestStart) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.running || m.watcher == nil {
+		return errors.New("watcher not running")
+	}
+	if len(req.WatchedDirs) == 0 {
+		return errors.New("no watch_dir provided")
+	}
+
+	if err := m.watcher.UpdateDirs(req.WatchedDirs, true); err != nil {
+		return err
+	}
+	m.watchedDirs.Store(&req.WatchedDirs)
+	return nil
+}
+
 func (m *Manager) Stop() error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -98,13 +172,40 @@ func (m *Manager) Stop() error {
 }
 
 // Status returns the current status of the watcher
-func (m *Manager) Status() (bool, []types.WatchDir, int) {
+func (m *Manager) Status() (bool, []types.WatchDir, int, types.RecursiveProgress) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 	if m.watcher == nil {
-		return false, nil, 0
+		return false, nil, 0, types.RecursiveProgress{}
 	}
 	return m.running, // is running
 		m.watcher.GetConfig().Dirs, // paths being watched
-		int(m.watcher.GetConfig().DebounceWindow.Seconds()) // cooldown in seconds
+		int(m.watcher.GetConfig().DebounceWindow.Seconds()), // cooldown in seconds
+		m.watcher.Progress() // recursive watch setup progress
+}
+
+// Progress returns the watcher's current recursive watch setup progress, or
+// a zero value if the watcher hasn't been started yet.
+func (m *Manager) Progress() types.RecursiveProgress {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.watcher == nil {
+		return types.RecursiveProgress{}
+	}
+	return m.watcher.Progress()
+}
+
+// BreakerStatus returns the circuit breaker's current state per service
+// (e.g. {"plex": "open"}), or nil if the breaker hasn't been configured yet.
+func (m *Manager) BreakerStatus() map[types.ServiceType]string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.registry.BreakerStatus()
+}
+
+// ResetBreaker manually clears every service's circuit breaker back to closed.
+func (m *Manager) ResetBreaker() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.registry.ResetBreaker()
 }
@@ -0,0 +1,117 @@
+// Package logger wraps slog with named per-subsystem loggers so operators
+// can enable verbose tracing for one noisy component (e.g. the recursive fs
+// watcher) without drowning the rest of the logs. This is the one trace
+// mechanism in the codebase - rather than adding a second, narrower
+// PLEXWATCHER_TRACE-reading package alongside it, LoadTraceEnv reads both
+// PW_TRACE and PLEXWATCHER_TRACE into the same subsystem set.
+package logger
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Subsystem names used throughout the codebase.
+const (
+	SubsystemFS        = "fs"
+	SubsystemPlex      = "plex"
+	SubsystemAbs       = "abs"
+	SubsystemJellyfin  = "jellyfin"
+	SubsystemAPI       = "api"
+	SubsystemScan      = "scan"
+	SubsystemDispatch  = "dispatch"
+	SubsystemRecursive = "recursive"
+)
+
+// aliases maps subsystem names operators might reasonably expect (matching
+// fsnotify upstream terminology) onto the name this package actually tags
+// records with, so PW_TRACE/PLEXWATCHER_TRACE=fsnotify does what it looks
+// like it should.
+var aliases = map[string]string{
+	"fsnotify": SubsystemFS,
+}
+
+var (
+	mu       sync.RWMutex
+	traced   = map[string]bool{}
+	traceAll bool
+	base     *slog.Logger = slog.Default()
+)
+
+// SetBase replaces the logger every sub-logger is derived from. Call this
+// once at startup after building the process-wide handler (console + any
+// rotating file sink); the caller owns handler construction so this package
+// stays agnostic of output formatting.
+func SetBase(l *slog.Logger) {
+	mu.Lock()
+	defer mu.Unlock()
+	base = l
+}
+
+// LoadTraceEnv parses PW_TRACE and PLEXWATCHER_TRACE (each a comma-separated
+// subsystem list, or "all") and stores which subsystems should have
+// Debug-level tracing enabled. Both names are accepted and merged so
+// operators following either naming convention get the same result;
+// PLEXWATCHER_TRACE=fsnotify,plex,abs,dispatch,recursive works exactly like
+// PW_TRACE with those same names.
+func LoadTraceEnv() {
+	mu.Lock()
+	defer mu.Unlock()
+	traced = parseTrace(os.Getenv("PW_TRACE"))
+	for k, v := range parseTrace(os.Getenv("PLEXWATCHER_TRACE")) {
+		traced[k] = v
+	}
+	_, traceAll = traced["all"]
+}
+
+func parseTrace(raw string) map[string]bool {
+	out := make(map[string]bool)
+	for _, s := range strings.Split(raw, ",") {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "" {
+			continue
+		}
+		if canonical, ok := aliases[s]; ok {
+			s = canonical
+		}
+		out[s] = true
+	}
+	return out
+}
+
+// Enabled reports whether PW_TRACE requested debug tracing for subsystem.
+func Enabled(subsystem string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return traceAll || traced[subsystem]
+}
+
+// For returns the sub-logger for subsystem, tagging every record with a
+// "subsystem" field so operators can grep/filter by it.
+func For(subsystem string) *slog.Logger {
+	mu.RLock()
+	l := base
+	mu.RUnlock()
+	return l.With("subsystem", subsystem)
+}
+
+// Trace logs msg at Debug level on subsystem's logger, but only builds and
+// emits the record when PW_TRACE has that subsystem (or "all") enabled -
+// callers can pass expensive fields (request bodies, full maps) without
+// paying for them when tracing is off. The record still goes through the
+// base handler's level filter: with a level above Debug (e.g. the default
+// LOG_LEVEL of INFO) it is dropped even when tracing is enabled.
+func Trace(subsystem string, msg string, args ...any) {
+	if !Enabled(subsystem) {
+		return
+	}
+	For(subsystem).Debug(msg, args...)
+}
+
+// With attaches contextual fields (request id, watch root, library id, ...)
+// to subsystem's logger.
+func With(subsystem string, args ...any) *slog.Logger {
+	return For(subsystem).With(args...)
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RotatingFile is a minimal size-based rotating io.Writer: once the current
+// file exceeds maxBytes, it's renamed with a numeric suffix and a fresh file
+// is opened in its place. Good enough for a trace sink; not intended to
+// replace a real log-rotation daemon.
+type RotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	keep     int
+	file     *os.File
+	size     int64
+}
+
+// NewRotatingFile opens (or creates) path for appending and rotates it once
+// it exceeds maxBytes, keeping up to keep rotated backups.
+func NewRotatingFile(path string, maxBytes int64, keep int) (*RotatingFile, error) {
+	if maxBytes <= 0 {
+		maxBytes = 10 << 20 // 10MB default
+	}
+	if keep <= 0 {
+		keep = 3
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("rotating file: mkdir: %w", err)
+	}
+
+	rf := &RotatingFile{path: path, maxBytes: maxBytes, keep: keep}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *RotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("rotating file: open %s: %w", rf.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("rotating file: stat %s: %w", rf.path, err)
+	}
+	rf.file = f
+	rf.size = info.Size()
+	return nil
+}
+
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.size+int64(len(p)) > rf.maxBytes {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+func (rf *RotatingFile) rotate() error {
+	rf.file.Close()
+
+	for i := rf.keep - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", rf.path, i)
+		dst := fmt.Sprintf("%s.%d", rf.path, i+1)
+		if _, err := os.Stat(src); err == nil {
+			os.Rename(src, dst)
+		}
+	}
+	if _, err := os.Stat(rf.path); err == nil {
+		os.Rename(rf.path, rf.path+".1")
+	}
+
+	return rf.open()
+}
+
+// Close closes the underlying file.
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	return rf.file.Close()
+}